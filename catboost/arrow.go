@@ -0,0 +1,180 @@
+//go:build arrow
+
+package catboost
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// PredictArrow returns predictions for Arrow record batch, columns are mapped to model features by name
+// and other columns are ignored. Float features are float32 (passed without copy if there are no nulls),
+// float64, int32 or int64 columns, nulls are NaN. Categorical features are string or integer columns,
+// dictionary-encoded columns are hashed once per dictionary value. Models with text or embedding
+// features are not supported. It returns Float64 array for one dimension of prediction and
+// FixedSizeList of Float64 otherwise, the array must be released by caller.
+// Build with tag `arrow`.
+func (m *Model) PredictArrow(rec arrow.Record) (arrow.Array, error) {
+	layout, err := m.featureLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	if layout.texts > 0 {
+		return nil, fmt.Errorf("%w: text features are not supported by PredictArrow", ErrInvalidRecord)
+	}
+
+	hasher, err := m.lib.NewCatHasher(0)
+	if err != nil {
+		return nil, err
+	}
+
+	nRows := int(rec.NumRows())
+	columns := make([][]float32, len(layout.schema.Features))
+
+	var missing []string
+	for i, feature := range layout.schema.Features {
+		indices := rec.Schema().FieldIndices(feature.Name)
+		if len(indices) == 0 {
+			missing = append(missing, feature.Name)
+			continue
+		}
+
+		column := rec.Column(indices[0])
+
+		if feature.Kind == CatFeature {
+			columns[i], err = arrowCatColumn(column, hasher)
+		} else {
+			columns[i], err = arrowFloatColumn(column)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: column %q: %w", ErrInvalidRecord, feature.Name, err)
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, &RecordError{Row: -1, Missing: missing}
+	}
+
+	preds, err := m.PredictFlatTransposedColumns(columns, nRows)
+	if err != nil {
+		return nil, err
+	}
+
+	return arrowPredictions(preds, nRows), nil
+}
+
+// arrowFloatColumn returns values of numeric column, float32 column without nulls isn't copied.
+func arrowFloatColumn(column arrow.Array) ([]float32, error) {
+	if c, ok := column.(*array.Float32); ok && c.NullN() == 0 {
+		return c.Float32Values(), nil
+	}
+
+	var value func(i int) float32
+
+	switch c := column.(type) {
+	case *array.Float32:
+		value = c.Value
+	case *array.Float64:
+		value = func(i int) float32 { return float32(c.Value(i)) }
+	case *array.Int32:
+		value = func(i int) float32 { return float32(c.Value(i)) }
+	case *array.Int64:
+		value = func(i int) float32 { return float32(c.Value(i)) }
+	default:
+		return nil, fmt.Errorf("unsupported type %s of float feature", column.DataType())
+	}
+
+	values := make([]float32, column.Len())
+	for i := range values {
+		if column.IsNull(i) {
+			values[i] = float32(math.NaN())
+			continue
+		}
+		values[i] = value(i)
+	}
+
+	return values, nil
+}
+
+// arrowCatColumn returns hashes of categorical column in flat layout (see CatHashToFloat).
+func arrowCatColumn(column arrow.Array, hasher *CatHasher) ([]float32, error) {
+	if column.NullN() > 0 {
+		return nil, fmt.Errorf("%d null values of categorical feature", column.NullN())
+	}
+
+	dict, ok := column.(*array.Dictionary)
+	if !ok {
+		return arrowCatHashes(column, hasher)
+	}
+
+	// Values of dictionary are hashed once
+	hashes, err := arrowCatHashes(dict.Dictionary(), hasher)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]float32, dict.Len())
+	for i := range values {
+		values[i] = hashes[dict.GetValueIndex(i)]
+	}
+
+	return values, nil
+}
+
+// arrowCatHashes returns hashes of string or integer values of column, nulls are skipped.
+func arrowCatHashes(column arrow.Array, hasher *CatHasher) ([]float32, error) {
+	var value func(i int) string
+
+	switch c := column.(type) {
+	case *array.String:
+		value = c.Value
+	case *array.LargeString:
+		value = c.Value
+	case *array.Binary:
+		value = c.ValueString
+	case *array.Int32:
+		value = func(i int) string { return strconv.FormatInt(int64(c.Value(i)), 10) }
+	case *array.Int64:
+		value = func(i int) string { return strconv.FormatInt(c.Value(i), 10) }
+	default:
+		return nil, fmt.Errorf("unsupported type %s of categorical feature", column.DataType())
+	}
+
+	hashes := make([]float32, column.Len())
+	for i := range hashes {
+		if !column.IsNull(i) {
+			hashes[i] = CatHashToFloat(hasher.String(value(i)))
+		}
+	}
+
+	return hashes, nil
+}
+
+// arrowPredictions returns predictions as Float64 array or FixedSizeList of Float64 for several dimensions,
+// buffer of predictions isn't copied.
+func arrowPredictions(preds []float64, nRows int) arrow.Array {
+	buffer := memory.NewBufferBytes(arrow.Float64Traits.CastToBytes(preds))
+
+	values := array.NewData(arrow.PrimitiveTypes.Float64, len(preds), []*memory.Buffer{nil, buffer}, nil, 0, 0)
+	defer values.Release()
+
+	if nRows == 0 || len(preds) == nRows {
+		return array.NewFloat64Data(values)
+	}
+
+	dimensions := len(preds) / nRows
+
+	list := array.NewData(
+		arrow.FixedSizeListOf(int32(dimensions), arrow.PrimitiveTypes.Float64),
+		nRows, []*memory.Buffer{nil}, []arrow.ArrayData{values}, 0, 0,
+	)
+	defer list.Release()
+
+	return array.NewFixedSizeListData(list)
+}
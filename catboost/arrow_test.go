@@ -0,0 +1,80 @@
+//go:build arrow
+
+package catboost_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	cb "github.com/mirecl/catboost-cgo/catboost"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPredictArrow(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	floats := [][]float32{{0.1, 0.2, 3, 4, 5, 0.6, 0.7, 0.8, 0.9, 1.0}, {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}}
+	cats := [][]string{{"a", "7"}, {"b", "7"}}
+
+	expected, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+
+	fields := []arrow.Field{{Name: "Extra", Type: arrow.BinaryTypes.String}}
+	for i := 0; i < 10; i++ {
+		dataType := arrow.DataType(arrow.PrimitiveTypes.Float32)
+		if i%2 == 1 {
+			dataType = arrow.PrimitiveTypes.Float64
+		}
+		fields = append(fields, arrow.Field{Name: "Column=" + strconv.Itoa(i), Type: dataType})
+	}
+	fields = append(fields,
+		arrow.Field{Name: "CatColumn_1", Type: &arrow.DictionaryType{
+			IndexType: arrow.PrimitiveTypes.Int8, ValueType: arrow.BinaryTypes.String,
+		}},
+		arrow.Field{Name: "CatColumn_2", Type: arrow.PrimitiveTypes.Int64},
+	)
+
+	builder := array.NewRecordBuilder(memory.NewGoAllocator(), arrow.NewSchema(fields, nil))
+	defer builder.Release()
+
+	for _, row := range floats {
+		builder.Field(0).(*array.StringBuilder).Append("ignored")
+		for i, value := range row {
+			switch b := builder.Field(i + 1).(type) {
+			case *array.Float32Builder:
+				b.Append(value)
+			case *array.Float64Builder:
+				b.Append(float64(value))
+			}
+		}
+	}
+	for _, row := range cats {
+		require.NoError(t, builder.Field(11).(*array.BinaryDictionaryBuilder).AppendString(row[0]))
+		value, err := strconv.ParseInt(row[1], 10, 64)
+		require.NoError(t, err)
+		builder.Field(12).(*array.Int64Builder).Append(value)
+	}
+
+	rec := builder.NewRecord()
+	defer rec.Release()
+
+	preds, err := model.PredictArrow(rec)
+	require.NoError(t, err)
+	defer preds.Release()
+
+	require.Equal(t, expected, preds.(*array.Float64).Float64Values())
+
+	missing := rec.NewSlice(0, 1)
+	defer missing.Release()
+
+	projected := array.NewRecord(arrow.NewSchema(fields[:5], nil), missing.Columns()[:5], 1)
+	defer projected.Release()
+
+	_, err = model.PredictArrow(projected)
+	require.ErrorIs(t, err, cb.ErrInvalidRecord)
+}
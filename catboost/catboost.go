@@ -9,12 +9,14 @@ package catboost
 import "C"
 
 import (
+	"crypto/sha256"
 	"errors"
 	"fmt"
-	"os"
+	"reflect"
 	"runtime"
 	"slices"
-	"strings"
+	"sync"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -49,6 +51,7 @@ const (
 	MetaTrainFinishTime = "train_finish_time"
 	MetaTraining        = "training"
 	MetaOutputOptions   = "output_options"
+	MetaClassParams     = "class_params"
 )
 
 var (
@@ -65,196 +68,173 @@ var (
 	ErrGetDevices                = errors.New("failed get list devices")
 	ErrEnabledGPU                = errors.New("failed enabled GPU")
 	ErrNotSupportedGPU           = errors.New("supported GPU only Linux")
+	ErrCalcModelPredictionStaged = errors.New("failed staged inference model")
+	ErrInvalidTreeRange          = errors.New("invalid tree range")
+	ErrCalcModelPredictionEmbed  = errors.New("failed inference model with embedding features")
+	ErrEmbeddingDimension        = errors.New("inconsistent embedding dimension")
+	ErrCalcModelPredictionHashed = errors.New("failed inference model with hashed categorical features")
+	ErrPredictSpecificClass      = errors.New("failed inference model for specific class")
+	ErrInvalidClassID            = errors.New("invalid class id")
+	ErrCalcModelPredictionFlat   = errors.New("failed inference model with flat features")
+	ErrFlatShape                 = errors.New("invalid shape of flat features")
+	ErrPoolClosed                = errors.New("pool is closed")
+	ErrPoolTimeout               = errors.New("timeout waiting for free model in pool")
+	ErrModelClosed               = errors.New("model is closed")
+	ErrInvalidShape              = errors.New("invalid shape of input data")
+	ErrNotSupportedByLibrary     = errors.New("not supported by loaded catboost library")
+	ErrEmptyModel                = errors.New("empty model")
+	ErrChecksumMismatch          = errors.New("model checksum mismatch")
+	ErrSchemaMismatch            = errors.New("model features schema mismatch")
+	ErrModelNotFound             = errors.New("model not found in registry")
+	ErrRegistryClosed            = errors.New("registry is closed")
+	ErrInvalidAlias              = errors.New("invalid model alias")
+	ErrGUIDMismatch              = errors.New("model guid mismatch")
+	ErrInvalidRecord             = errors.New("invalid record")
 )
 
 var catboostSharedLibraryPath = ""
 
-// Version returns version catboost.
+var (
+	defaultLibraryMu sync.Mutex
+	defaultLibrary   atomic.Pointer[Library]
+)
+
+// Version returns version catboost of header used for build.
+// Use LibraryInfo for version of library loaded at runtime.
 func Version() string {
 	return fmt.Sprintf("v%d.%d.%d", C.CATBOOST_APPLIER_MAJOR, C.CATBOOST_APPLIER_MINOR, C.CATBOOST_APPLIER_FIX)
 }
 
 // SetSharedLibraryPath set library catboost path.
+// It has effect only before the library is loaded by first model.
 func SetSharedLibraryPath(path string) {
+	defaultLibraryMu.Lock()
+	defer defaultLibraryMu.Unlock()
+
 	catboostSharedLibraryPath = path
 }
 
-func initialization() error {
-	if !checkPlatform() {
-		return ErrNotSupportedPlatform
+// DefaultLibrary returns library used by package-level functions, it is loaded once by path
+// from SetSharedLibraryPath, CATBOOST_LIBRARY_PATH or first found in SetLibrarySearchPaths.
+// It is safe for concurrent use, failed loading is not cached, so it is retried by next call.
+func DefaultLibrary() (*Library, error) {
+	if lib := defaultLibrary.Load(); lib != nil {
+		return lib, nil
 	}
 
-	if err := initSharedLibraryPath(); err != nil {
-		return err
+	defaultLibraryMu.Lock()
+	defer defaultLibraryMu.Unlock()
+
+	if lib := defaultLibrary.Load(); lib != nil {
+		return lib, nil
+	}
+
+	if !checkPlatform() {
+		return nil, ErrNotSupportedPlatform
 	}
 
-	cName := C.CString(catboostSharedLibraryPath)
-	defer C.free(unsafe.Pointer(cName))
-
-	handle := C.dlopen(cName, C.RTLD_LAZY)
-	if handle == nil {
-		msg := C.GoString(C.dlerror())
-		return fmt.Errorf("%w `%s`: %s", ErrLoadLibrary, catboostSharedLibraryPath, msg)
-	}
-
-	lib := library{handle}
-
-	// Load function from CatBoost shared library
-	lib.RegisterFn("ModelCalcerCreate")
-	lib.RegisterFn("ModelCalcerDelete")
-	lib.RegisterFn("LoadFullModelFromBuffer")
-	lib.RegisterFn("CalcModelPredictionSingle")
-	lib.RegisterFn("CalcModelPrediction")
-	lib.RegisterFn("CalcModelPredictionText")
-	lib.RegisterFn("GetErrorString")
-	lib.RegisterFn("GetFloatFeaturesCount")
-	lib.RegisterFn("GetCatFeaturesCount")
-	lib.RegisterFn("GetTextFeaturesCount")
-	lib.RegisterFn("GetDimensionsCount")
-	lib.RegisterFn("SetPredictionTypeString")
-	lib.RegisterFn("GetModelUsedFeaturesNames")
-	lib.RegisterFn("GetModelInfoValue")
-	lib.RegisterFn("GetCatFeatureIndices")
-	lib.RegisterFn("GetFloatFeatureIndices")
-	lib.RegisterFn("GetTextFeatureIndices")
-	lib.RegisterFn("GetSupportedEvaluatorTypes")
-	lib.RegisterFn("EnableGPUEvaluation")
+	lib, err := findLibrary()
+	if err != nil {
+		return nil, err
+	}
 
-	return nil
-}
+	defaultLibrary.Store(lib)
 
-type library struct {
-	handle unsafe.Pointer
+	return lib, nil
 }
 
-//nolint:funlen
-func (l *library) RegisterFn(fnName string) {
-	fnC := getFromLibraryFn(l.handle, fnName)
-
-	switch fnName {
-	case "ModelCalcerCreate":
-		C.SetModelCalcerCreateFn(fnC)
-	case "LoadFullModelFromBuffer":
-		C.SetLoadFullModelFromBufferFn(fnC)
-	case "CalcModelPredictionSingle":
-		C.SetCalcModelPredictionSingleFn(fnC)
-	case "CalcModelPrediction":
-		C.SetCalcModelPredictionFn(fnC)
-	case "CalcModelPredictionText":
-		C.SetCalcModelPredictionTextFn(fnC)
-	case "GetErrorString":
-		C.SetGetErrorStringFn(fnC)
-	case "GetFloatFeaturesCount":
-		C.SetGetFloatFeaturesCountFn(fnC)
-	case "GetCatFeaturesCount":
-		C.SetGetCatFeaturesCountFn(fnC)
-	case "GetTextFeaturesCount":
-		C.SetGetTextFeaturesCountFn(fnC)
-	case "SetPredictionTypeString":
-		C.SetSetPredictionTypeStringFn(fnC)
-	case "GetDimensionsCount":
-		C.SetGetDimensionsCountFn(fnC)
-	case "GetModelUsedFeaturesNames":
-		C.SetGetModelUsedFeaturesNamesFn(fnC)
-	case "GetModelInfoValue":
-		C.SetGetModelInfoValueFn(fnC)
-	case "GetCatFeatureIndices":
-		C.SetGetCatFeatureIndicesFn(fnC)
-	case "GetFloatFeatureIndices":
-		C.SetGetFloatFeatureIndicesFn(fnC)
-	case "GetTextFeatureIndices":
-		C.SetGetTextFeatureIndicesFn(fnC)
-	case "GetSupportedEvaluatorTypes":
-		C.SetGetSupportedEvaluatorTypesFn(fnC)
-	case "EnableGPUEvaluation":
-		C.SetGetEnableGPUEvaluationFn(fnC)
-	case "ModelCalcerDelete":
-		C.SetModelCalcerDeleteFn(fnC)
-	default:
-		panic(fmt.Sprintf("not supported function from catboost library: %s", fnName))
-	}
-}
-
-func initSharedLibraryPath() error {
-	if catboostSharedLibraryPath == "" {
-		catboostSharedLibraryPath = os.Getenv("CATBOOST_LIBRARY_PATH")
-	}
-
-	if catboostSharedLibraryPath == "" {
-		catboostSharedLibraryPath = fmt.Sprintf("/usr/local/lib/libcatboostmodel.%s", getExt())
-	}
-
-	if _, err := os.Stat(catboostSharedLibraryPath); errors.Is(err, os.ErrNotExist) {
-		return fmt.Errorf("%w: %s", ErrNotFoundLibrary, catboostSharedLibraryPath)
-	}
+// SetDefaultLibrary sets library used by package-level functions instead of loading it by path.
+// Models loaded before keep using their library.
+func SetDefaultLibrary(lib *Library) {
+	defaultLibraryMu.Lock()
+	defer defaultLibraryMu.Unlock()
 
-	return nil
+	defaultLibrary.Store(lib)
 }
 
 func checkPlatform() bool {
 	return slices.Contains([]string{"darwin", "linux"}, runtime.GOOS)
 }
 
-func getExt() string {
-	ext := "dylib"
-
-	if runtime.GOOS == "linux" {
-		ext = "so"
-	}
-
-	return ext
-}
-
 // LoadFullModelFromFile returns load model from file into given model handle.
 func LoadFullModelFromFile(filename string) (*Model, error) {
-	b, err := os.ReadFile(filename)
+	lib, err := DefaultLibrary()
 	if err != nil {
-		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+		return nil, err
 	}
 
-	return LoadFullModelFromBuffer(b)
+	return lib.LoadFullModelFromFile(filename)
 }
 
 // LoadFullModelFromBuffer returns load model from memory buffer into given model handle.
+// The buffer must not be modified after loading, it is used for PredictWith.
 func LoadFullModelFromBuffer(buffer []byte) (*Model, error) {
-	if err := initialization(); err != nil {
+	lib, err := DefaultLibrary()
+	if err != nil {
 		return nil, err
 	}
 
-	handler := C.WrapModelCalcerCreate()
-
-	if !C.WrapLoadFullModelFromBuffer(handler, unsafe.Pointer(&buffer[0]), C.size_t(len(buffer))) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromBuffer, GetError())
-	}
-
-	return &Model{handler: handler, predictionType: RawFormulaVal}, nil
+	return lib.LoadFullModelFromBuffer(buffer)
 }
 
 // Model is a wrapper over ModelCalcerHandle.
 type Model struct {
+	lib            *Library
 	handler        unsafe.Pointer
 	predictionType PredictionType
+
+	// buffer or filename is used for load handles with another prediction type (see PredictWith).
+	buffer   []byte
+	filename string
+	// mmap is true if model is loaded by LoadFullModelFromMmap.
+	mmap bool
+	// checksum is SHA-256 of loaded buffer or file, see Fingerprint.
+	checksum [sha256.Size]byte
+
+	mu    sync.Mutex
+	typed map[PredictionType]*Model
+	// layout of named features and bindings of struct types, see PredictRecords and PredictStructs.
+	layout   *featureLayout
+	bindings map[reflect.Type]*structBinding
+
+	// skipValidation disables validation of input data, see SetInputValidation.
+	skipValidation atomic.Bool
+
+	// state of handle, see Close.
+	stateMu sync.RWMutex
+	closed  bool
+	inUse   sync.WaitGroup
 }
 
 // GetModelInfoValue returns model metainfo for some key.
-// If key is missing in model metainfo storage this method will return "".
+// If key is missing in model metainfo storage or model is closed this method will return "".
 func (m *Model) GetModelInfoValue(key string) string {
+	if err := m.acquire(); err != nil {
+		return ""
+	}
+	defer m.release()
+
 	keyC := C.CString(key)
 	defer C.free(unsafe.Pointer(keyC))
 
-	valueC := C.WrapGetModelInfoValue(m.handler, keyC, C.size_t(len(key)))
+	valueC := C.WrapGetModelInfoValue(m.lib.fns, m.handler, keyC, C.size_t(len(key)))
 	return C.GoString(valueC)
 }
 
 // SetPredictionType set prediction type for model evaluation.
-// Not use in concurrency mode!!!
+// Not use in concurrency mode!!! Use PredictWith for concurrency mode.
 // Recommend set prediction type after load model.
 func (m *Model) SetPredictionType(p PredictionType) error {
+	if err := m.acquire(); err != nil {
+		return err
+	}
+	defer m.release()
+
 	pC := C.CString(string(p))
 	defer C.free(unsafe.Pointer(pC))
 
-	if !C.WrapSetPredictionTypeString(m.handler, pC) {
-		return fmt.Errorf("%w `%s`: %s", ErrSetPredictionType, p, GetError().Error())
+	if !C.WrapSetPredictionTypeString(m.lib.fns, m.handler, pC) {
+		return m.lib.newError("SetPredictionTypeString", ErrSetPredictionType, nil)
 	}
 
 	m.predictionType = p
@@ -264,14 +244,22 @@ func (m *Model) SetPredictionType(p PredictionType) error {
 
 // GetSupportedEvaluatorTypes returns supported formula evaluator types.
 func (m *Model) GetSupportedEvaluatorTypes() ([]EvaluatorType, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetSupportedEvaluatorTypes"); err != nil {
+		return nil, err
+	}
+
 	devicesNum := uint64(2)
 
-	devicesTmp := make([]*uint64, devicesNum)
-	devicesC := (*C.size_t)(devicesTmp[0])
-	defer C.free(unsafe.Pointer(devicesC))
+	var devicesC *C.size_t
+	defer func() { C.free(unsafe.Pointer(devicesC)) }()
 
-	if !C.WrapGetSupportedEvaluatorTypes(m.handler, &devicesC, (*C.size_t)(&devicesNum)) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrGetDevices, GetError())
+	if !C.WrapGetSupportedEvaluatorTypes(m.lib.fns, m.handler, &devicesC, (*C.size_t)(&devicesNum)) {
+		return nil, m.lib.newError("GetSupportedEvaluatorTypes", ErrGetDevices, nil)
 	}
 
 	devicesCTmp := (*[1 << 28]C.int)(unsafe.Pointer(devicesC))[:devicesNum:devicesNum]
@@ -287,10 +275,19 @@ func (m *Model) GetSupportedEvaluatorTypes() ([]EvaluatorType, error) {
 // Only device 0 is supported for "now"
 // See more details https://github.com/catboost/catboost/issues/2774
 func (m *Model) EnableGPUEvaluation() error {
+	if err := m.acquire(); err != nil {
+		return err
+	}
+	defer m.release()
+
 	if runtime.GOOS != "linux" {
 		return ErrNotSupportedGPU
 	}
 
+	if err := m.lib.checkSymbols("EnableGPUEvaluation"); err != nil {
+		return err
+	}
+
 	devices, err := m.GetSupportedEvaluatorTypes()
 	if err != nil {
 		return err
@@ -302,8 +299,8 @@ func (m *Model) EnableGPUEvaluation() error {
 
 	deviceID := 0
 
-	if !C.WrapEnableGPUEvaluation(m.handler, C.int(deviceID)) {
-		return fmt.Errorf(formatErrorMessage, ErrEnabledGPU, GetError())
+	if !C.WrapEnableGPUEvaluation(m.lib.fns, m.handler, C.int(deviceID)) {
+		return m.lib.newError("EnableGPUEvaluation", ErrEnabledGPU, nil)
 	}
 
 	return nil
@@ -311,14 +308,19 @@ func (m *Model) EnableGPUEvaluation() error {
 
 // GetModelUsedFeaturesNames returns names of features used in the model.
 func (m *Model) GetModelUsedFeaturesNames() ([]string, error) {
-	featuresCount := m.GetFeaturesCount()
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	featuresCount := m.featuresCount()
 
 	featuresC := C.makeCharArray1D(C.int(featuresCount))
 	defer C.freeCharArray1D(featuresC, C.int(featuresCount))
 
 	featuresCountC := C.size_t(featuresCount)
-	if !C.WrapGetModelUsedFeaturesNames(m.handler, &featuresC, &featuresCountC) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrGetModelUsedFeaturesNames, GetError())
+	if !C.WrapGetModelUsedFeaturesNames(m.lib.fns, m.handler, &featuresC, &featuresCountC) {
+		return nil, m.lib.newError("GetModelUsedFeaturesNames", ErrGetModelUsedFeaturesNames, nil)
 	}
 
 	features := make([]string, 0, featuresCount)
@@ -335,40 +337,141 @@ func (m *Model) GetModelUsedFeaturesNames() ([]string, error) {
 
 // GetFloatFeaturesCount returns expected float feature count for model.
 func (m *Model) GetFloatFeaturesCount() int {
-	return int(C.WrapGetFloatFeaturesCount(m.handler))
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.floatFeaturesCount()
 }
 
 // GetCatFeaturesCount returns expected categorical feature count for model.
 func (m *Model) GetCatFeaturesCount() int {
-	return int(C.WrapGetCatFeaturesCount(m.handler))
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.catFeaturesCount()
 }
 
 // GetTextFeaturesCount returns expected text feature count for model.
+// It returns 0 if loaded library doesn't support text features.
 func (m *Model) GetTextFeaturesCount() int {
-	return int(C.WrapGetTextFeaturesCount(m.handler))
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.textFeaturesCount()
+}
+
+// GetEmbeddingFeaturesCount returns expected embedding feature count for model.
+// It returns 0 if loaded library doesn't support embedding features.
+func (m *Model) GetEmbeddingFeaturesCount() int {
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.embeddingFeaturesCount()
 }
 
 // GetFeaturesCount returns all expected feature count for model.
 func (m *Model) GetFeaturesCount() int {
-	return m.GetCatFeaturesCount() + m.GetFloatFeaturesCount() + m.GetTextFeaturesCount()
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.featuresCount()
 }
 
 // GetDimensionsCount returns number of dimensions in model.
 func (m *Model) GetDimensionsCount() int {
-	return int(C.WrapGetDimensionsCount(m.handler))
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.dimensionsCount()
+}
+
+// GetTreeCount returns number of trees in model, 0 if not supported by loaded library.
+func (m *Model) GetTreeCount() int {
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.treeCount()
 }
 
 // GetRowResultSize return size row result.
 func (m *Model) GetRowResultSize() int {
+	if err := m.acquire(); err != nil {
+		return 0
+	}
+	defer m.release()
+
+	return m.rowResultSize()
+}
+
+func (m *Model) floatFeaturesCount() int {
+	return int(C.WrapGetFloatFeaturesCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) catFeaturesCount() int {
+	return int(C.WrapGetCatFeaturesCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) textFeaturesCount() int {
+	if m.lib.checkSymbols("GetTextFeaturesCount") != nil {
+		return 0
+	}
+
+	return int(C.WrapGetTextFeaturesCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) embeddingFeaturesCount() int {
+	if m.lib.checkSymbols("GetEmbeddingFeaturesCount") != nil {
+		return 0
+	}
+
+	return int(C.WrapGetEmbeddingFeaturesCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) featuresCount() int {
+	return m.catFeaturesCount() + m.floatFeaturesCount() + m.textFeaturesCount() + m.embeddingFeaturesCount()
+}
+
+func (m *Model) dimensionsCount() int {
+	return int(C.WrapGetDimensionsCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) treeCount() int {
+	if m.lib.checkSymbols("GetTreeCount") != nil {
+		return 0
+	}
+
+	return int(C.WrapGetTreeCount(m.lib.fns, m.handler))
+}
+
+func (m *Model) rowResultSize() int {
 	if m.predictionType == Class {
 		return 1
 	}
 
-	return m.GetDimensionsCount()
+	return m.dimensionsCount()
 }
 
 // Predict returns predictions.
 func (m *Model) Predict(floats [][]float32, cats [][]string) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
 	var nSamples int
 
 	// Get length sample
@@ -377,11 +480,22 @@ func (m *Model) Predict(floats [][]float32, cats [][]string) ([]float64, error)
 		nSamples = len(cats)
 	}
 
-	floatFeaturesCount := m.GetFloatFeaturesCount()
-	catFeaturesCount := m.GetCatFeaturesCount()
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
 
 	// Special for Multiclassification (size > 1)
-	size := m.GetRowResultSize()
+	size := m.rowResultSize()
 
 	preds := make([]float64, nSamples*size)
 
@@ -389,9 +503,10 @@ func (m *Model) Predict(floats [][]float32, cats [][]string) ([]float64, error)
 	defer C.free(unsafe.Pointer(floatsC))
 
 	catsC := makeCharArray2D(cats)
-	defer C.freeCharArray2D(catsC, C.int(len(cats)), C.int(catFeaturesCount))
+	defer freeCharArray2D(catsC, cats)
 
 	if !C.WrapCalcModelPrediction(
+		m.lib.fns,
 		m.handler,
 		C.size_t(nSamples),
 		floatsC,
@@ -401,7 +516,9 @@ func (m *Model) Predict(floats [][]float32, cats [][]string) ([]float64, error)
 		(*C.double)(&preds[0]),
 		C.size_t(len(preds)),
 	) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrCalcModelPrediction, GetError())
+		return nil, m.lib.newError("CalcModelPrediction", ErrCalcModelPrediction, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+		})
 	}
 
 	return preds, nil
@@ -409,10 +526,22 @@ func (m *Model) Predict(floats [][]float32, cats [][]string) ([]float64, error)
 
 // PredictSingle returns prediction.
 func (m *Model) PredictSingle(floats []float32, cats []string) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.checkInput(
+		checkRow(FloatFeature, floats, m.floatFeaturesCount()),
+		checkRow(CatFeature, cats, m.catFeaturesCount()),
+	); err != nil {
+		return nil, err
+	}
+
 	catsC := makeCharArray1D(cats)
 	defer C.freeCharArray1D(catsC, C.int(len(cats)))
 
-	size := m.GetRowResultSize()
+	size := m.rowResultSize()
 	preds := make([]float64, 1*size)
 
 	floatsC := new(C.float)
@@ -421,6 +550,7 @@ func (m *Model) PredictSingle(floats []float32, cats []string) ([]float64, error
 	}
 
 	if !C.WrapCalcModelPredictionSingle(
+		m.lib.fns,
 		m.handler,
 		floatsC,
 		C.size_t(len(floats)),
@@ -428,7 +558,9 @@ func (m *Model) PredictSingle(floats []float32, cats []string) ([]float64, error
 		C.size_t(len(cats)),
 		(*C.double)(&preds[0]),
 		C.size_t(len(preds))) {
-		return nil, GetError()
+		return nil, m.lib.newError("CalcModelPredictionSingle", ErrCalcModelPrediction, &Shape{
+			Samples: 1, FloatFeatures: len(floats), CatFeatures: len(cats),
+		})
 	}
 
 	return preds, nil
@@ -436,6 +568,15 @@ func (m *Model) PredictSingle(floats []float32, cats []string) ([]float64, error
 
 // PredictText returns predictions for samples with text features.
 func (m *Model) PredictText(floats [][]float32, cats [][]string, texts [][]string) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("CalcModelPredictionText", "GetTextFeaturesCount"); err != nil {
+		return nil, err
+	}
+
 	var nSamples int
 
 	// Get length sample
@@ -447,12 +588,24 @@ func (m *Model) PredictText(floats [][]float32, cats [][]string, texts [][]strin
 		nSamples = len(texts)
 	}
 
-	floatFeaturesCount := m.GetFloatFeaturesCount()
-	catFeaturesCount := m.GetCatFeaturesCount()
-	textFeaturesCount := m.GetTextFeaturesCount()
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
 
 	// Special for Multiclassification (size > 1)
-	size := m.GetRowResultSize()
+	size := m.rowResultSize()
 
 	preds := make([]float64, nSamples*size)
 
@@ -460,12 +613,13 @@ func (m *Model) PredictText(floats [][]float32, cats [][]string, texts [][]strin
 	defer C.free(unsafe.Pointer(floatsC))
 
 	catsC := makeCharArray2D(cats)
-	defer C.freeCharArray2D(catsC, C.int(len(cats)), C.int(catFeaturesCount))
+	defer freeCharArray2D(catsC, cats)
 
 	textsC := makeCharArray2D(texts)
-	defer C.freeCharArray2D(textsC, C.int(len(texts)), C.int(textFeaturesCount))
+	defer freeCharArray2D(textsC, texts)
 
 	if !C.WrapCalcModelPredictionText(
+		m.lib.fns,
 		m.handler,
 		C.size_t(nSamples),
 		floatsC,
@@ -477,7 +631,9 @@ func (m *Model) PredictText(floats [][]float32, cats [][]string, texts [][]strin
 		(*C.double)(&preds[0]),
 		C.size_t(len(preds)),
 	) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrCalcModelPredictionText, GetError())
+		return nil, m.lib.newError("CalcModelPredictionText", ErrCalcModelPredictionText, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount, TextFeatures: textFeaturesCount,
+		})
 	}
 
 	return preds, nil
@@ -488,148 +644,892 @@ func (m *Model) PredictSingleText(floats []float32, cats []string, texts []strin
 	return m.PredictText([][]float32{floats}, [][]string{cats}, [][]string{texts})
 }
 
-// Delete model handle.
-func (m *Model) Delete() {
-	C.WrapModelCalcerDelete(m.handler)
-}
-
-// Transform change data for result Multiclassification.
-func (m *Model) Transform(preds []float64) [][]float64 {
-	size := m.GetRowResultSize()
-	result := make([][]float64, 0, len(preds)/size)
+// PredictTextAndEmbeddings returns predictions for samples with text and embedding features.
+// Embeddings are passed per sample as one vector per embedding feature.
+//
+//nolint:funlen
+func (m *Model) PredictTextAndEmbeddings(
+	floats [][]float32, cats [][]string, texts [][]string, embeddings [][][]float32,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
 
-	for i := 0; i < len(preds); i += size {
-		pred := make([]float64, 0, size)
-		for j := i; j < i+size; j++ {
-			pred = append(pred, preds[j])
-		}
-		result = append(result, pred)
+	if err := m.lib.checkSymbols("CalcModelPredictionTextAndEmbeddings"); err != nil {
+		return nil, err
 	}
 
-	return result
-}
+	var nSamples int
 
-// GetCatFeatureIndices expected indices of category features used in the model.
-func (m *Model) GetCatFeatureIndices() ([]uint64, error) {
-	catsFeatureNum := uint64(m.GetCatFeaturesCount())
-	if catsFeatureNum == 0 {
-		return []uint64{}, nil
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(cats)
+	}
+	if nSamples == 0 {
+		nSamples = len(texts)
+	}
+	if nSamples == 0 {
+		nSamples = len(embeddings)
 	}
 
-	catsFeatureIndices := make([]*uint64, catsFeatureNum)
-	catsFeatureIndicesC := (*C.size_t)(catsFeatureIndices[0])
-	defer C.free(unsafe.Pointer(catsFeatureIndicesC))
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+	embeddingFeaturesCount := m.embeddingFeaturesCount()
 
-	if !C.WrapGetCatFeatureIndices(m.handler, &catsFeatureIndicesC, (*C.size_t)(&catsFeatureNum)) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrGetIndices, GetError())
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
 	}
 
-	indices := (*[1 << 28]uint64)(unsafe.Pointer(catsFeatureIndicesC))[:catsFeatureNum:catsFeatureNum]
-	return indices, nil
-}
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
 
-// GetFloatFeatureIndices expected indices of float features used in the model.
-func (m *Model) GetFloatFeatureIndices() ([]uint64, error) {
-	floatsFeatureNum := uint64(m.GetFloatFeaturesCount())
-	if floatsFeatureNum == 0 {
-		return []uint64{}, nil
+	dimensions, err := getEmbeddingDimensions(embeddings, nSamples, embeddingFeaturesCount)
+	if err != nil {
+		return nil, err
 	}
 
-	floatsFeatureIndices := make([]*uint64, floatsFeatureNum)
-	floatsFeatureIndicesC := (*C.size_t)(floatsFeatureIndices[0])
-	defer C.free(unsafe.Pointer(floatsFeatureIndicesC))
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
 
-	if !C.WrapGetFloatFeatureIndices(m.handler, &floatsFeatureIndicesC, (*C.size_t)(&floatsFeatureNum)) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrGetIndices, GetError())
-	}
+	preds := make([]float64, nSamples*size)
 
-	indices := (*[1 << 28]uint64)(unsafe.Pointer(floatsFeatureIndicesC))[:floatsFeatureNum:floatsFeatureNum]
-	return indices, nil
-}
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
 
-// GetTextFeatureIndices expected indices of text features used in the model.
-func (m *Model) GetTextFeatureIndices() ([]uint64, error) {
-	textsFeatureNum := uint64(m.GetTextFeaturesCount())
-	if textsFeatureNum == 0 {
-		return []uint64{}, nil
-	}
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
 
-	textsFeatureIndices := make([]*uint64, textsFeatureNum)
-	textsFeatureIndicesC := (*C.size_t)(textsFeatureIndices[0])
-	defer C.free(unsafe.Pointer(textsFeatureIndicesC))
+	embeddingsC := makeFloatArray3D(embeddings)
+	defer C.freeFloatArray3D(embeddingsC, C.int(len(embeddings)))
 
-	if !C.WrapGetTextFeatureIndices(m.handler, &textsFeatureIndicesC, (*C.size_t)(&textsFeatureNum)) {
-		return nil, fmt.Errorf(formatErrorMessage, ErrGetIndices, GetError())
+	if !C.WrapCalcModelPredictionTextAndEmbeddings(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		embeddingsC,
+		getSizePointer(dimensions),
+		C.size_t(embeddingFeaturesCount),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("CalcModelPredictionTextAndEmbeddings", ErrCalcModelPredictionEmbed, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+			TextFeatures: textFeaturesCount, EmbeddingFeatures: embeddingFeaturesCount,
+		})
 	}
 
-	indices := (*[1 << 28]uint64)(unsafe.Pointer(textsFeatureIndicesC))[:textsFeatureNum:textsFeatureNum]
-	return indices, nil
+	return preds, nil
 }
 
-// getFromLibraryFn retruns point to function from CatBoost shared memory.
-func getFromLibraryFn(handle unsafe.Pointer, fnName string) unsafe.Pointer {
-	cFnName := C.CString(fnName)
-	defer C.free(unsafe.Pointer(cFnName))
+// PredictSingleTextAndEmbeddings returns prediction for a single sample with text and embedding features.
+func (m *Model) PredictSingleTextAndEmbeddings(
+	floats []float32, cats []string, texts []string, embeddings [][]float32,
+) ([]float64, error) {
+	return m.PredictTextAndEmbeddings([][]float32{floats}, [][]string{cats}, [][]string{texts}, [][][]float32{embeddings})
+}
 
-	fn := C.dlsym(handle, cFnName)
-	if fn == nil {
-		msg := C.GoString(C.dlerror())
-		panic(fmt.Sprintf("Error looking up %s in `%s`: %s", fnName, catboostSharedLibraryPath, msg))
+// PredictHashed returns predictions for samples with hashed categorical features.
+// Use CatHasher for calculate hashes of categorical values.
+func (m *Model) PredictHashed(floats [][]float32, catHashes [][]int32) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
 	}
+	defer m.release()
 
-	return fn
-}
+	if err := m.lib.checkSymbols("CalcModelPredictionWithHashedCatFeatures"); err != nil {
+		return nil, err
+	}
 
-// GetError returns last error from model.
-// If error ocured will return stored exception message.
-// If no error ocured, will return invalid pointer.
-func GetError() error {
-	messageC := C.WrapGetErrorString()
-	message := C.GoString(messageC)
+	var nSamples int
 
-	i := strings.Index(message, "catboost.git")
-	if i == -1 {
-		return nil
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(catHashes)
 	}
 
-	return errors.New(message[i:])
-}
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
 
-// Helper for create convert [][]string to `C`.
-func makeCharArray2D(cats [][]string) ***C.char {
-	nSamples := len(cats)
-	catsC := C.makeCharArray2D(C.int(nSamples))
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
 
-	for i, cat := range cats {
-		catC := C.makeCharArray1D(C.int(len(cat)))
-		for i, c := range cat {
-			C.setCharArray1D(catC, C.CString(c), C.int(i))
-		}
-		C.setCharArray2D(catsC, catC, C.int(i))
+	if nSamples == 0 {
+		return []float64{}, nil
 	}
 
-	return catsC
-}
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
 
-// Helper for create convert []string to `C`.
-func makeCharArray1D(cats []string) **C.char {
-	nSamples := len(cats)
-	catsC := C.makeCharArray1D(C.int(nSamples))
+	preds := make([]float64, nSamples*size)
 
-	for i := range cats {
-		C.setCharArray1D(catsC, C.CString(cats[i]), C.int(i))
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	if !C.WrapCalcModelPredictionWithHashedCatFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("CalcModelPredictionWithHashedCatFeatures", ErrCalcModelPredictionHashed, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+		})
 	}
 
-	return catsC
+	return preds, nil
 }
 
-// Helper for create convert [][]float32 to `C`.
-func makeFloatArray2D(floats [][]float32) **C.float {
-	nSamples := len(floats)
-	floatsC := C.makeFloatArray2D(C.int(nSamples))
+// PredictHashedText returns predictions for samples with hashed categorical and text features.
+func (m *Model) PredictHashedText(floats [][]float32, catHashes [][]int32, texts [][]string) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
 
-	for i, v := range floats {
-		C.setFloatArray2D(floatsC, (*C.float)(&v[0]), C.int(i))
+	if err := m.lib.checkSymbols("CalcModelPredictionWithHashedCatFeaturesAndTextFeatures"); err != nil {
+		return nil, err
+	}
+
+	var nSamples int
+
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(catHashes)
+	}
+	if nSamples == 0 {
+		nSamples = len(texts)
+	}
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	preds := make([]float64, nSamples*size)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	if !C.WrapCalcModelPredictionWithHashedCatFeaturesAndTextFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError(
+			"CalcModelPredictionWithHashedCatFeaturesAndTextFeatures", ErrCalcModelPredictionHashed, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+				TextFeatures: textFeaturesCount,
+			},
+		)
+	}
+
+	return preds, nil
+}
+
+// PredictHashedTextAndEmbeddings returns predictions for samples with hashed categorical,
+// text and embedding features.
+//
+//nolint:funlen
+func (m *Model) PredictHashedTextAndEmbeddings(
+	floats [][]float32, catHashes [][]int32, texts [][]string, embeddings [][][]float32,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures"); err != nil {
+		return nil, err
+	}
+
+	var nSamples int
+
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(catHashes)
+	}
+	if nSamples == 0 {
+		nSamples = len(texts)
+	}
+	if nSamples == 0 {
+		nSamples = len(embeddings)
+	}
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+	embeddingFeaturesCount := m.embeddingFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	dimensions, err := getEmbeddingDimensions(embeddings, nSamples, embeddingFeaturesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	preds := make([]float64, nSamples*size)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	embeddingsC := makeFloatArray3D(embeddings)
+	defer C.freeFloatArray3D(embeddingsC, C.int(len(embeddings)))
+
+	if !C.WrapCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		embeddingsC,
+		getSizePointer(dimensions),
+		C.size_t(embeddingFeaturesCount),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError(
+			"CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures", ErrCalcModelPredictionHashed, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+				TextFeatures: textFeaturesCount, EmbeddingFeatures: embeddingFeaturesCount,
+			},
+		)
+	}
+
+	return preds, nil
+}
+
+// PredictStaged returns predictions for every stage of the ensemble.
+// Stage k uses trees in range [treeStart; treeStart+step*k) and the last stage
+// uses trees in range [treeStart; treeEnd). If treeEnd is 0, all trees are used.
+func (m *Model) PredictStaged(floats [][]float32, cats [][]string, treeStart, treeEnd, step int) ([][]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetTreeCount", "CalcModelPredictionStaged"); err != nil {
+		return nil, err
+	}
+
+	stages, err := m.getStages(treeStart, treeEnd, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var nSamples int
+
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(cats)
+	}
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		result := make([][]float64, len(stages))
+		for i := range result {
+			result[i] = []float64{}
+		}
+
+		return result, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	result := make([][]float64, 0, len(stages))
+
+	for _, stageEnd := range stages {
+		preds := make([]float64, nSamples*size)
+
+		if !C.WrapCalcModelPredictionStaged(
+			m.lib.fns,
+			m.handler,
+			C.size_t(nSamples),
+			C.size_t(treeStart),
+			C.size_t(stageEnd),
+			floatsC,
+			C.size_t(floatFeaturesCount),
+			catsC,
+			C.size_t(catFeaturesCount),
+			(*C.double)(&preds[0]),
+			C.size_t(len(preds)),
+		) {
+			return nil, m.lib.newError("CalcModelPredictionStaged", ErrCalcModelPredictionStaged, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+			})
+		}
+
+		result = append(result, preds)
+	}
+
+	return result, nil
+}
+
+// PredictSingleStaged returns prediction for every stage of the ensemble for a single sample.
+// See PredictStaged for details about tree range.
+func (m *Model) PredictSingleStaged(
+	floats []float32, cats []string, treeStart, treeEnd, step int,
+) ([][]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetTreeCount", "CalcModelPredictionSingleStaged"); err != nil {
+		return nil, err
+	}
+
+	stages, err := m.getStages(treeStart, treeEnd, step)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.checkInput(
+		checkRow(FloatFeature, floats, m.floatFeaturesCount()),
+		checkRow(CatFeature, cats, m.catFeaturesCount()),
+	); err != nil {
+		return nil, err
+	}
+
+	catsC := makeCharArray1D(cats)
+	defer C.freeCharArray1D(catsC, C.int(len(cats)))
+
+	size := m.rowResultSize()
+
+	floatsC := new(C.float)
+	if len(floats) > 0 {
+		floatsC = (*C.float)(&floats[0])
+	}
+
+	result := make([][]float64, 0, len(stages))
+
+	for _, stageEnd := range stages {
+		preds := make([]float64, 1*size)
+
+		if !C.WrapCalcModelPredictionSingleStaged(
+			m.lib.fns,
+			m.handler,
+			C.size_t(treeStart),
+			C.size_t(stageEnd),
+			floatsC,
+			C.size_t(len(floats)),
+			catsC,
+			C.size_t(len(cats)),
+			(*C.double)(&preds[0]),
+			C.size_t(len(preds))) {
+			return nil, m.lib.newError("CalcModelPredictionSingleStaged", ErrCalcModelPredictionStaged, &Shape{
+				Samples: 1, FloatFeatures: len(floats), CatFeatures: len(cats),
+			})
+		}
+
+		result = append(result, preds)
+	}
+
+	return result, nil
+}
+
+// PredictTextStaged returns predictions for every stage of the ensemble for samples with text features.
+// See PredictStaged for details about tree range.
+//
+//nolint:funlen
+func (m *Model) PredictTextStaged(
+	floats [][]float32, cats [][]string, texts [][]string, treeStart, treeEnd, step int,
+) ([][]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetTreeCount", "CalcModelPredictionTextStaged"); err != nil {
+		return nil, err
+	}
+
+	stages, err := m.getStages(treeStart, treeEnd, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var nSamples int
+
+	// Get length sample
+	nSamples = len(floats)
+	if nSamples == 0 {
+		nSamples = len(cats)
+	}
+	if nSamples == 0 {
+		nSamples = len(texts)
+	}
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		result := make([][]float64, len(stages))
+		for i := range result {
+			result[i] = []float64{}
+		}
+
+		return result, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	result := make([][]float64, 0, len(stages))
+
+	for _, stageEnd := range stages {
+		preds := make([]float64, nSamples*size)
+
+		if !C.WrapCalcModelPredictionTextStaged(
+			m.lib.fns,
+			m.handler,
+			C.size_t(nSamples),
+			C.size_t(treeStart),
+			C.size_t(stageEnd),
+			floatsC,
+			C.size_t(floatFeaturesCount),
+			catsC,
+			C.size_t(catFeaturesCount),
+			textsC,
+			C.size_t(textFeaturesCount),
+			(*C.double)(&preds[0]),
+			C.size_t(len(preds)),
+		) {
+			return nil, m.lib.newError("CalcModelPredictionTextStaged", ErrCalcModelPredictionStaged, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+				TextFeatures: textFeaturesCount,
+			})
+		}
+
+		result = append(result, preds)
+	}
+
+	return result, nil
+}
+
+// getStages returns the end of tree range for every stage.
+func (m *Model) getStages(treeStart, treeEnd, step int) ([]int, error) {
+	treeCount := m.treeCount()
+
+	if treeEnd == 0 {
+		treeEnd = treeCount
+	}
+
+	if treeStart < 0 || treeStart >= treeEnd || treeEnd > treeCount || step <= 0 {
+		return nil, fmt.Errorf(
+			"%w: start=%d end=%d step=%d (tree count %d)", ErrInvalidTreeRange, treeStart, treeEnd, step, treeCount,
+		)
+	}
+
+	stages := make([]int, 0, (treeEnd-treeStart+step-1)/step)
+	for end := treeStart + step; end < treeEnd; end += step {
+		stages = append(stages, end)
+	}
+
+	return append(stages, treeEnd), nil
+}
+
+// Delete model handle, it is the same as Close.
+func (m *Model) Delete() {
+	_ = m.Close()
+}
+
+// Transform change data for result Multiclassification.
+func (m *Model) Transform(preds []float64) [][]float64 {
+	return transform(preds, m.GetRowResultSize())
+}
+
+// transform split predictions into rows of size.
+func transform(preds []float64, size int) [][]float64 {
+	if size <= 0 {
+		return nil
+	}
+
+	result := make([][]float64, 0, len(preds)/size)
+
+	for i := 0; i < len(preds); i += size {
+		pred := make([]float64, 0, size)
+		for j := i; j < i+size; j++ {
+			pred = append(pred, preds[j])
+		}
+		result = append(result, pred)
+	}
+
+	return result
+}
+
+// GetCatFeatureIndices expected indices of category features used in the model.
+func (m *Model) GetCatFeatureIndices() ([]uint64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	catsFeatureNum := uint64(m.catFeaturesCount())
+	if catsFeatureNum == 0 {
+		return []uint64{}, nil
+	}
+
+	var catsFeatureIndicesC *C.size_t
+	// The array is allocated by CatBoost, the closure frees the pointer set by the call
+	defer func() { C.free(unsafe.Pointer(catsFeatureIndicesC)) }()
+
+	if !C.WrapGetCatFeatureIndices(m.lib.fns, m.handler, &catsFeatureIndicesC, (*C.size_t)(&catsFeatureNum)) {
+		return nil, m.lib.newError("GetCatFeatureIndices", ErrGetIndices, nil)
+	}
+
+	indices := (*[1 << 28]uint64)(unsafe.Pointer(catsFeatureIndicesC))[:catsFeatureNum:catsFeatureNum]
+	return slices.Clone(indices), nil
+}
+
+// GetFloatFeatureIndices expected indices of float features used in the model.
+func (m *Model) GetFloatFeatureIndices() ([]uint64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	floatsFeatureNum := uint64(m.floatFeaturesCount())
+	if floatsFeatureNum == 0 {
+		return []uint64{}, nil
+	}
+
+	var floatsFeatureIndicesC *C.size_t
+	defer func() { C.free(unsafe.Pointer(floatsFeatureIndicesC)) }()
+
+	if !C.WrapGetFloatFeatureIndices(m.lib.fns, m.handler, &floatsFeatureIndicesC, (*C.size_t)(&floatsFeatureNum)) {
+		return nil, m.lib.newError("GetFloatFeatureIndices", ErrGetIndices, nil)
+	}
+
+	indices := (*[1 << 28]uint64)(unsafe.Pointer(floatsFeatureIndicesC))[:floatsFeatureNum:floatsFeatureNum]
+	return slices.Clone(indices), nil
+}
+
+// GetTextFeatureIndices expected indices of text features used in the model.
+func (m *Model) GetTextFeatureIndices() ([]uint64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetTextFeatureIndices"); err != nil {
+		return nil, err
+	}
+
+	textsFeatureNum := uint64(m.textFeaturesCount())
+	if textsFeatureNum == 0 {
+		return []uint64{}, nil
+	}
+
+	var textsFeatureIndicesC *C.size_t
+	defer func() { C.free(unsafe.Pointer(textsFeatureIndicesC)) }()
+
+	if !C.WrapGetTextFeatureIndices(m.lib.fns, m.handler, &textsFeatureIndicesC, (*C.size_t)(&textsFeatureNum)) {
+		return nil, m.lib.newError("GetTextFeatureIndices", ErrGetIndices, nil)
+	}
+
+	indices := (*[1 << 28]uint64)(unsafe.Pointer(textsFeatureIndicesC))[:textsFeatureNum:textsFeatureNum]
+	return slices.Clone(indices), nil
+}
+
+// GetEmbeddingFeatureIndices expected indices of embedding features used in the model.
+func (m *Model) GetEmbeddingFeatureIndices() ([]uint64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("GetEmbeddingFeatureIndices"); err != nil {
+		return nil, err
+	}
+
+	embeddingsFeatureNum := uint64(m.embeddingFeaturesCount())
+	if embeddingsFeatureNum == 0 {
+		return []uint64{}, nil
+	}
+
+	var embeddingsFeatureIndicesC *C.size_t
+	defer func() { C.free(unsafe.Pointer(embeddingsFeatureIndicesC)) }()
+
+	if !C.WrapGetEmbeddingFeatureIndices(
+		m.lib.fns, m.handler, &embeddingsFeatureIndicesC, (*C.size_t)(&embeddingsFeatureNum),
+	) {
+		return nil, m.lib.newError("GetEmbeddingFeatureIndices", ErrGetIndices, nil)
+	}
+
+	indices := (*[1 << 28]uint64)(unsafe.Pointer(embeddingsFeatureIndicesC))[:embeddingsFeatureNum:embeddingsFeatureNum]
+	return slices.Clone(indices), nil
+}
+
+// GetError returns last error from model.
+// If error ocured will return stored exception message.
+// If no error ocured, will return nil.
+// Failed calls of Model return *Error with the message, prefer it to GetError.
+func GetError() error {
+	lib := defaultLibrary.Load()
+	if lib == nil {
+		return nil
+	}
+
+	messageC := C.WrapGetErrorString(lib.fns)
+
+	message := trimNativeMessage(C.GoString(messageC))
+	if message == "" {
+		return nil
+	}
+
+	return errors.New(message)
+}
+
+// Helper for create convert [][]string to `C`.
+func makeCharArray2D(cats [][]string) ***C.char {
+	nSamples := len(cats)
+	catsC := C.makeCharArray2D(C.int(nSamples))
+
+	for i, cat := range cats {
+		catC := C.makeCharArray1D(C.int(len(cat)))
+		for i, c := range cat {
+			C.setCharArray1D(catC, C.CString(c), C.int(i))
+		}
+		C.setCharArray2D(catsC, catC, C.int(i))
+	}
+
+	return catsC
+}
+
+// Helper for free `C` array created by makeCharArray2D.
+func freeCharArray2D(catsC ***C.char, cats [][]string) {
+	catsTmpC := unsafe.Slice(catsC, len(cats))
+	for i, cat := range cats {
+		C.freeCharArray1D(catsTmpC[i], C.int(len(cat)))
+	}
+	C.free(unsafe.Pointer(catsC))
+}
+
+// Helper for create convert []string to `C`.
+func makeCharArray1D(cats []string) **C.char {
+	nSamples := len(cats)
+	catsC := C.makeCharArray1D(C.int(nSamples))
+
+	for i := range cats {
+		C.setCharArray1D(catsC, C.CString(cats[i]), C.int(i))
+	}
+
+	return catsC
+}
+
+// Helper for create convert [][]int32 to `C`.
+func makeIntArray2D(ints [][]int32) **C.int {
+	nSamples := len(ints)
+	intsC := C.makeIntArray2D(C.int(nSamples))
+
+	for i, v := range ints {
+		if len(v) > 0 {
+			C.setIntArray2D(intsC, (*C.int)(unsafe.Pointer(&v[0])), C.int(i))
+		}
+	}
+
+	return intsC
+}
+
+// Helper for create convert [][][]float32 to `C`.
+func makeFloatArray3D(embeddings [][][]float32) ***C.float {
+	nSamples := len(embeddings)
+	embeddingsC := C.makeFloatArray3D(C.int(nSamples))
+
+	for i, row := range embeddings {
+		rowC := C.makeFloatArray2D(C.int(len(row)))
+		for j, v := range row {
+			if len(v) > 0 {
+				C.setFloatArray2D(rowC, (*C.float)(&v[0]), C.int(j))
+			}
+		}
+		C.setFloatArray3D(embeddingsC, rowC, C.int(i))
+	}
+
+	return embeddingsC
+}
+
+// getEmbeddingDimensions returns dimension of every embedding feature.
+// All samples must have the same dimension for the same embedding feature.
+func getEmbeddingDimensions(embeddings [][][]float32, nSamples, featuresCount int) ([]C.size_t, error) {
+	if featuresCount > 0 && len(embeddings) != nSamples {
+		return nil, fmt.Errorf("%w: got embeddings for %d samples, expected %d",
+			ErrEmbeddingDimension, len(embeddings), nSamples)
+	}
+
+	dimensions := make([]C.size_t, featuresCount)
+	if len(embeddings) == 0 {
+		return dimensions, nil
+	}
+
+	for j := 0; j < featuresCount && j < len(embeddings[0]); j++ {
+		dimensions[j] = C.size_t(len(embeddings[0][j]))
+	}
+
+	for i, row := range embeddings {
+		if len(row) != featuresCount {
+			return nil, fmt.Errorf("%w: sample %d has %d embedding features, expected %d",
+				ErrEmbeddingDimension, i, len(row), featuresCount)
+		}
+
+		for j, v := range row {
+			if C.size_t(len(v)) != dimensions[j] {
+				return nil, fmt.Errorf("%w: sample %d feature %d has dimension %d, expected %d",
+					ErrEmbeddingDimension, i, j, len(v), dimensions[j])
+			}
+		}
+	}
+
+	return dimensions, nil
+}
+
+// getSizePointer returns pointer to the first element or nil for empty slice.
+func getSizePointer(values []C.size_t) *C.size_t {
+	if len(values) == 0 {
+		return nil
+	}
+
+	return &values[0]
+}
+
+// getSamplesCount returns the first non-zero count of samples.
+func getSamplesCount(counts ...int) int {
+	for _, count := range counts {
+		if count != 0 {
+			return count
+		}
+	}
+
+	return 0
+}
+
+// Helper for create convert [][]float32 to `C`.
+func makeFloatArray2D(floats [][]float32) **C.float {
+	nSamples := len(floats)
+	floatsC := C.makeFloatArray2D(C.int(nSamples))
+
+	for i, v := range floats {
+		if len(v) > 0 {
+			C.setFloatArray2D(floatsC, (*C.float)(&v[0]), C.int(i))
+		}
 	}
 
 	return floatsC
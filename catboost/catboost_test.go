@@ -1,11 +1,28 @@
 package catboost_test
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	cb "github.com/mirecl/catboost-cgo/catboost"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,6 +32,8 @@ const (
 	testModelPathMulticlassification = "../example/multiclassification/multiclassification.cbm"
 	testModelPathMetadata            = "../example/metadata/metadata.cbm"
 	testModelPathText                = "../example/text/text.cbm"
+	testModelPathEmbedding           = "../example/embedding/embedding.cbm"
+	testPredsPathEmbedding           = "../example/embedding/embedding.json"
 )
 
 func TestVersion(t *testing.T) {
@@ -294,3 +313,1310 @@ func TestPredictTextMatchesSingle(t *testing.T) {
 
 	require.Equal(t, batchPreds, singlePreds)
 }
+
+func TestPredictStaged(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	treeCount := model.GetTreeCount()
+	require.Greater(t, treeCount, 1)
+
+	floats := [][]float32{{2, 4, 6, 8, 5}, {1, 4, 50, 60, 5}}
+	cats := [][]string{{"a", "b"}, {"a", "d"}}
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+
+	stages, err := model.PredictStaged(floats, cats, 0, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, stages, treeCount)
+	require.InDeltaSlice(t, preds, stages[len(stages)-1], 1e-9)
+
+	stages, err = model.PredictStaged(floats, cats, 0, treeCount, treeCount)
+	require.NoError(t, err)
+	require.Len(t, stages, 1)
+	require.InDeltaSlice(t, preds, stages[0], 1e-9)
+
+	single, err := model.PredictSingleStaged(floats[0], cats[0], 0, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, single, treeCount)
+	require.InDeltaSlice(t, preds[:1], single[len(single)-1], 1e-9)
+
+	_, err = model.PredictStaged(floats, cats, 0, treeCount+1, 1)
+	require.ErrorIs(t, err, cb.ErrInvalidTreeRange)
+
+	_, err = model.PredictStaged(floats, cats, 0, 0, 0)
+	require.ErrorIs(t, err, cb.ErrInvalidTreeRange)
+}
+
+func TestPredictTextStaged(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathText)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	floats := [][]float32{{4.6, 100.0}, {1.5, 35.0}}
+	cats := [][]string{{}, {}}
+	texts := [][]string{{"amazing value"}, {"poor quality"}}
+
+	stages, err := model.PredictTextStaged(floats, cats, texts, 0, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, stages, (model.GetTreeCount()+1)/2)
+	require.InDeltaSlice(t, []float64{1.3351632373725695, -1.2562312927248545}, stages[len(stages)-1], 1e-9)
+}
+
+func TestEmbeddingFeatures(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathText)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	require.Equal(t, 0, model.GetEmbeddingFeaturesCount())
+
+	embeddingIndices, err := model.GetEmbeddingFeatureIndices()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{}, embeddingIndices)
+
+	floats := [][]float32{{4.6, 100.0}, {1.5, 35.0}}
+	cats := [][]string{{}, {}}
+	texts := [][]string{{"amazing value"}, {"poor quality"}}
+
+	preds, err := model.PredictTextAndEmbeddings(floats, cats, texts, [][][]float32{{}, {}})
+	require.NoError(t, err)
+	require.Equal(t, []float64{1.3351632373725695, -1.2562312927248545}, preds)
+
+	preds, err = model.PredictSingleTextAndEmbeddings(floats[0], cats[0], texts[0], nil)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1.3351632373725695}, preds)
+
+	_, err = model.PredictTextAndEmbeddings(floats, cats, texts, [][][]float32{{{1, 2}}, {}})
+	require.ErrorIs(t, err, cb.ErrEmbeddingDimension)
+}
+
+func TestPredictEmbeddings(t *testing.T) {
+	// Model and its predictions are written by example/embedding/embedding.py
+	b, err := os.ReadFile(testPredsPathEmbedding)
+	require.NoError(t, err)
+
+	var expected struct {
+		Floats     [][]float32   `json:"floats"`
+		Embeddings [][][]float32 `json:"embeddings"`
+		Preds      []float64     `json:"preds"`
+	}
+	require.NoError(t, json.Unmarshal(b, &expected))
+	require.Len(t, expected.Preds, 2)
+
+	model, err := cb.LoadFullModelFromFile(testModelPathEmbedding)
+	require.NoError(t, err)
+	defer model.Close()
+
+	require.Equal(t, 1, model.GetEmbeddingFeaturesCount())
+	require.Equal(t, 1, model.GetFloatFeaturesCount())
+
+	embeddingIndices, err := model.GetEmbeddingFeatureIndices()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{1}, embeddingIndices)
+
+	cats := [][]string{{}, {}}
+	texts := [][]string{{}, {}}
+
+	preds, err := model.PredictTextAndEmbeddings(expected.Floats, cats, texts, expected.Embeddings)
+	require.NoError(t, err)
+	require.InDeltaSlice(t, expected.Preds, preds, 1e-9)
+
+	preds, err = model.PredictHashedTextAndEmbeddings(expected.Floats, [][]int32{{}, {}}, texts, expected.Embeddings)
+	require.NoError(t, err)
+	require.InDeltaSlice(t, expected.Preds, preds, 1e-9)
+
+	for i := range expected.Preds {
+		preds, err := model.PredictSingleTextAndEmbeddings(expected.Floats[i], nil, nil, expected.Embeddings[i])
+		require.NoError(t, err)
+		require.InDeltaSlice(t, expected.Preds[i:i+1], preds, 1e-9)
+	}
+
+	// Predictions depend on embedding
+	preds, err = model.PredictTextAndEmbeddings(
+		expected.Floats, cats, texts, [][][]float32{expected.Embeddings[1], expected.Embeddings[0]},
+	)
+	require.NoError(t, err)
+	require.NotEqual(t, expected.Preds[0], preds[0])
+
+	_, err = model.PredictTextAndEmbeddings(expected.Floats, cats, texts, [][][]float32{{{1, 2, 3, 4}}, {{1, 2}}})
+	require.ErrorIs(t, err, cb.ErrEmbeddingDimension)
+}
+
+func TestPredictHashed(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	hasher, err := cb.NewCatHasher(16)
+	require.NoError(t, err)
+
+	floats := [][]float32{{2, 4, 6, 8, 5}, {1, 4, 50, 60, 5}}
+	cats := [][]string{{"a", "b"}, {"a", "d"}}
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+
+	predsHashed, err := model.PredictHashed(floats, hasher.Rows(cats))
+	require.NoError(t, err)
+	require.Equal(t, preds, predsHashed)
+
+	require.Equal(t, hasher.String("a"), hasher.Row([]string{"a"})[0])
+	require.Equal(t, hasher.String("42"), hasher.Integer(42))
+}
+
+func TestPredictHashedText(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathText)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	floats := [][]float32{{4.6, 100.0}, {1.5, 35.0}}
+	texts := [][]string{{"amazing value"}, {"poor quality"}}
+
+	preds, err := model.PredictHashedText(floats, [][]int32{{}, {}}, texts)
+	require.NoError(t, err)
+	require.Equal(t, []float64{1.3351632373725695, -1.2562312927248545}, preds)
+}
+
+func TestPredictClass(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMulticlassification)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	err = model.SetPredictionType(cb.Probablity)
+	require.NoError(t, err)
+
+	floats := [][]float32{{1996, 197}, {1968, 37}}
+	cats := [][]string{{"winter"}, {"winter"}}
+
+	preds, err := model.PredictClass(2, floats, cats)
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float64{0.5131288055561035, 0.8653931063389221}, preds, 1e-9)
+
+	pred, err := model.PredictSingleClass(2, floats[0], cats[0])
+	require.NoError(t, err)
+	require.InDelta(t, 0.5131288055561035, pred, 1e-9)
+
+	hasher, err := cb.NewCatHasher(0)
+	require.NoError(t, err)
+
+	preds, err = model.PredictClassHashed(2, floats, hasher.Rows(cats))
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float64{0.5131288055561035, 0.8653931063389221}, preds, 1e-9)
+
+	_, err = model.PredictClass(3, floats, cats)
+	require.ErrorIs(t, err, cb.ErrInvalidClassID)
+
+	_, err = model.PredictSingleClass(-1, floats[0], cats[0])
+	require.ErrorIs(t, err, cb.ErrInvalidClassID)
+}
+
+func TestPredictFlat(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	preds, err := model.PredictFlat([]float32{2, 4, 6, 8, 1, 4, 50, 60}, 2, 4)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+
+	preds, err = model.PredictFlatStride([]float32{2, 4, 6, 8, 0, 1, 4, 50, 60}, 2, 4, 5)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+
+	preds, err = model.PredictFlatTransposed([]float32{2, 1, 4, 4, 6, 50, 8, 60}, 2, 4)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+
+	preds, err = model.PredictFlat(nil, 0, 4)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	_, err = model.PredictFlat([]float32{2, 4, 6, 8, 1, 4, 50}, 2, 4)
+	require.ErrorIs(t, err, cb.ErrFlatShape)
+
+	_, err = model.PredictFlatStride([]float32{2, 4, 6, 8, 1, 4, 50, 60}, 2, 4, 3)
+	require.ErrorIs(t, err, cb.ErrFlatShape)
+
+	_, err = model.PredictFlat([]float32{2, 4, 6, 8, 1, 4, 50, 60}, 1, 8)
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	_, err = model.PredictFlatTransposed([]float32{2, 1, 4, 4, 6, 50}, 2, 3)
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+}
+
+func TestPredictFlatTransposedColumns(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathClassifier)
+	require.NoError(t, err)
+	defer model.Close()
+
+	hasher, err := cb.NewCatHasher(0)
+	require.NoError(t, err)
+
+	expected, err := model.Predict([][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}, [][]string{{"a", "b"}, {"a", "d"}})
+	require.NoError(t, err)
+
+	// Categorical features have flat indices 0 and 1, float features 2-5
+	columns := [][]float32{
+		{cb.CatHashToFloat(hasher.String("a")), cb.CatHashToFloat(hasher.String("a"))},
+		{cb.CatHashToFloat(hasher.String("b")), cb.CatHashToFloat(hasher.String("d"))},
+		{2, 1}, {4, 4}, {6, 50}, {8, 60},
+	}
+
+	preds, err := model.PredictFlatTransposedColumns(columns, 2)
+	require.NoError(t, err)
+	require.Equal(t, expected, preds)
+
+	columns[5] = columns[5][:1]
+	_, err = model.PredictFlatTransposedColumns(columns, 2)
+	require.ErrorIs(t, err, cb.ErrFlatShape)
+}
+
+func TestPredictWith(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMulticlassification)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+	defer model.Delete()
+
+	floats := [][]float32{{1996, 197}, {1968, 37}}
+	cats := [][]string{{"winter"}, {"winter"}}
+
+	testCases := []struct {
+		predictType cb.PredictionType
+		preds       []float64
+	}{
+		{
+			predictType: cb.Class,
+			preds:       []float64{2, 2},
+		},
+		{
+			predictType: cb.Probablity,
+			preds:       []float64{0.2006095939361826, 0.2862616005077138, 0.5131288055561035, 0.07388963079437862, 0.060717262866699366, 0.8653931063389221},
+		},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for _, testCase := range testCases {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				preds, err := model.PredictWith(context.Background(), cb.PredictOptions{Type: testCase.predictType}, floats, cats)
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.preds, preds)
+			}()
+		}
+	}
+	wg.Wait()
+
+	probs, err := model.PredictWith(context.Background(), cb.PredictOptions{Type: cb.Probablity}, floats, cats)
+	require.NoError(t, err)
+	require.Len(t, model.TransformWith(cb.PredictOptions{Type: cb.Probablity}, probs), 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = model.PredictWith(ctx, cb.PredictOptions{}, floats, cats)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, err = model.PredictWith(context.Background(), cb.PredictOptions{Type: "Fake"}, floats, cats)
+	require.ErrorIs(t, err, cb.ErrSetPredictionType)
+}
+
+func TestPredictWithReplacedFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.cbm")
+
+	data, err := os.ReadFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, data, 0o600))
+
+	model, err := cb.LoadFullModelFromFile(filename)
+	require.NoError(t, err)
+	defer model.Close()
+
+	data, err = os.ReadFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, data, 0o600))
+
+	_, err = model.PredictWith(context.Background(), cb.PredictOptions{Type: cb.Probablity}, nil, nil)
+	require.ErrorIs(t, err, cb.ErrChecksumMismatch)
+
+	// The prediction type of the model is served without reloading.
+	_, err = model.PredictWith(context.Background(), cb.PredictOptions{Type: cb.RawFormulaVal}, nil, nil)
+	require.NoError(t, err)
+}
+
+func TestPool(t *testing.T) {
+	pool, err := cb.NewPoolFromFile(testModelPathRegressor, cb.PoolOptions{Size: 4, Timeout: time.Second})
+	require.NoError(t, err)
+	require.Equal(t, 4, pool.Size())
+
+	floats := [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}
+	cats := [][]string{{}, {}}
+
+	// Stress test, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 16; j++ {
+				preds, err := pool.Predict(context.Background(), floats, cats)
+				assert.NoError(t, err)
+				assert.Equal(t, []float64{15.625, 18.125}, preds)
+			}
+		}()
+	}
+	wg.Wait()
+
+	pool.Close()
+	pool.Close()
+
+	_, err = pool.Predict(context.Background(), floats, cats)
+	require.ErrorIs(t, err, cb.ErrPoolClosed)
+}
+
+func TestPoolCloseContext(t *testing.T) {
+	pool, err := cb.NewPoolFromFile(testModelPathRegressor, cb.PoolOptions{Size: 2})
+	require.NoError(t, err)
+
+	model, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	// The model isn't returned, so pool can't be closed till timeout
+	require.ErrorIs(t, pool.CloseContext(ctx), context.DeadlineExceeded)
+
+	_, err = pool.Get(context.Background())
+	require.ErrorIs(t, err, cb.ErrPoolClosed)
+
+	pool.Put(model)
+	require.NoError(t, pool.CloseContext(context.Background()))
+}
+
+func TestPoolTimeout(t *testing.T) {
+	pool, err := cb.NewPoolFromFile(testModelPathRegressor, cb.PoolOptions{Size: 1, Timeout: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer pool.Close()
+
+	model, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	_, err = pool.Get(context.Background())
+	require.ErrorIs(t, err, cb.ErrPoolTimeout)
+
+	pool.Put(model)
+
+	model, err = pool.Get(context.Background())
+	require.NoError(t, err)
+	pool.Put(model)
+
+	// Double Put and foreign model would block pool
+	require.Panics(t, func() { pool.Put(model) })
+
+	foreign, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+	require.NoError(t, err)
+	defer foreign.Close()
+	require.Panics(t, func() { pool.Put(foreign) })
+
+	_, err = cb.NewPoolFromFile("fake.cbm", cb.PoolOptions{})
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+}
+
+func TestClose(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	var closer io.Closer = model
+
+	floats := [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}
+	cats := [][]string{{}, {}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				preds, err := model.Predict(floats, cats)
+				if errors.Is(err, cb.ErrModelClosed) {
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, []float64{15.625, 18.125}, preds)
+			}
+		}()
+	}
+
+	require.NoError(t, closer.Close())
+	wg.Wait()
+
+	require.NoError(t, model.Close())
+	model.Delete()
+
+	_, err = model.Predict(floats, cats)
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	_, err = model.PredictSingle(floats[0], cats[0])
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	_, err = model.GetFloatFeatureIndices()
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	_, err = model.GetModelUsedFeaturesNames()
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	_, err = model.PredictWith(context.Background(), cb.PredictOptions{}, floats, cats)
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	err = model.SetPredictionType(cb.Probablity)
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	require.Equal(t, 0, model.GetFloatFeaturesCount())
+	require.Equal(t, "", model.GetModelInfoValue(cb.MetaModelGUID))
+	require.Nil(t, model.Transform([]float64{1, 2}))
+}
+
+func TestCloseFinalizer(t *testing.T) {
+	open := cb.OpenModels()
+
+	for i := 0; i < 8; i++ {
+		model, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+		require.NoError(t, err)
+		require.Equal(t, 4, model.GetFloatFeaturesCount())
+	}
+	require.Greater(t, cb.OpenModels(), open)
+
+	// Models are unreachable, handles are released by finalizers
+	require.Eventually(t, func() bool {
+		runtime.GC()
+		return cb.OpenModels() <= open
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestLoadFullModelFromBufferInvalid(t *testing.T) {
+	model, err := cb.LoadFullModelFromBuffer([]byte("not a model"))
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromBuffer)
+	require.Nil(t, model)
+}
+
+func TestError(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	// Regressor expects 4 float features
+	_, err = model.PredictFlat([]float32{2, 4, 1, 4}, 2, 2)
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	// Native errors of CatBoost without validation
+	model.SetInputValidation(false)
+	_, err = model.PredictFlat([]float32{2, 4, 1, 4}, 2, 2)
+	require.ErrorIs(t, err, cb.ErrCalcModelPredictionFlat)
+
+	var cbErr *cb.Error
+	require.ErrorAs(t, err, &cbErr)
+	require.Equal(t, "CalcModelPredictionFlat", cbErr.Op)
+	require.Equal(t, cb.ErrCalcModelPredictionFlat, cbErr.Sentinel)
+	require.NotEmpty(t, cbErr.NativeMessage)
+	require.NotEmpty(t, cbErr.File)
+	require.Greater(t, cbErr.Line, 0)
+	require.Equal(t, &cb.Shape{Samples: 2, FloatFeatures: 2}, cbErr.Shape)
+
+	_, err = model.PredictSingle([]float32{2, 4}, []string{})
+	require.ErrorIs(t, err, cb.ErrCalcModelPrediction)
+	require.ErrorAs(t, err, &cbErr)
+	require.Equal(t, "CalcModelPredictionSingle", cbErr.Op)
+	require.Equal(t, &cb.Shape{Samples: 1, FloatFeatures: 2}, cbErr.Shape)
+
+	err = model.SetPredictionType("Fake")
+	require.ErrorIs(t, err, cb.ErrSetPredictionType)
+	require.ErrorAs(t, err, &cbErr)
+	require.Nil(t, cbErr.Shape)
+
+	_, err = cb.LoadFullModelFromBuffer([]byte("not a model"))
+	require.ErrorAs(t, err, &cbErr)
+	require.Equal(t, "LoadFullModelFromBuffer", cbErr.Op)
+}
+
+func TestInputValidation(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NotNil(t, model)
+
+	testCases := []struct {
+		floats [][]float32
+		cats   [][]string
+		err    *cb.ShapeError
+	}{
+		{
+			floats: [][]float32{{2, 4, 6, 8}, {1, 4, 50}},
+			cats:   [][]string{{"a", "b"}, {"a", "d"}},
+			err:    &cb.ShapeError{Kind: cb.FloatFeature, Row: 1, Got: 3, Expected: 4},
+		},
+		{
+			floats: [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60, 70}},
+			cats:   [][]string{{"a", "b"}, {"a", "d"}},
+			err:    &cb.ShapeError{Kind: cb.FloatFeature, Row: 1, Got: 5, Expected: 4},
+		},
+		{
+			floats: [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}},
+			cats:   [][]string{{"a", "b"}, {"a"}},
+			err:    &cb.ShapeError{Kind: cb.CatFeature, Row: 1, Got: 1, Expected: 2},
+		},
+		{
+			floats: [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}},
+			cats:   [][]string{{"a", "b"}, {"a", "d", "e"}},
+			err:    &cb.ShapeError{Kind: cb.CatFeature, Row: 1, Got: 3, Expected: 2},
+		},
+		{
+			floats: [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}},
+			cats:   [][]string{{"a", "b"}},
+			err:    &cb.ShapeError{Kind: cb.CatFeature, Row: -1, Got: 1, Expected: 2},
+		},
+		{
+			floats: [][]float32{{2, 4, 6, 8}},
+			cats:   nil,
+			err:    &cb.ShapeError{Kind: cb.CatFeature, Row: -1, Got: 0, Expected: 1},
+		},
+	}
+
+	for i, testCase := range testCases {
+		label := fmt.Sprintf("testCase[%d]", i)
+		t.Run(label, func(t *testing.T) {
+			_, err := model.Predict(testCase.floats, testCase.cats)
+			require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+			var shapeErr *cb.ShapeError
+			require.ErrorAs(t, err, &shapeErr)
+			require.Equal(t, testCase.err, shapeErr)
+		})
+	}
+
+	_, err = model.PredictSingle([]float32{2, 4, 6, 8}, []string{"a"})
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	_, err = model.PredictSingle([]float32{2, 4, 6, 8, 10}, []string{"a", "b"})
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	// Flat layout holds categorical and float features
+	_, err = model.PredictFlatTransposedColumns([][]float32{{2}, {4}, {6}, {8}}, 1)
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	_, err = model.PredictStaged([][]float32{{2, 4, 6}}, [][]string{{"a", "b"}}, 0, 0, 1)
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	_, err = model.PredictHashed([][]float32{{2, 4, 6, 8}}, [][]int32{{1}})
+	require.ErrorIs(t, err, cb.ErrInvalidShape)
+
+	// Trusted input without validation
+	model.SetInputValidation(false)
+	preds, err := model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{"a", "b"}})
+	require.NoError(t, err)
+	require.Len(t, preds, 1)
+}
+
+func TestConcurrentLoad(t *testing.T) {
+	// Library is loaded once by first model, run with -race
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			model, err := cb.LoadFullModelFromFile(testModelPathRegressor)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer model.Close()
+
+			preds, err := model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+			assert.NoError(t, err)
+			assert.Equal(t, []float64{15.625}, preds)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLibraryInfo(t *testing.T) {
+	info, err := cb.LibraryInfo()
+	require.NoError(t, err)
+	require.NotEmpty(t, info.Path)
+	require.Contains(t, info.Symbols, "CalcModelPrediction")
+	require.True(t, info.Capabilities.Text)
+	require.True(t, info.Capabilities.Embeddings)
+	require.True(t, info.Capabilities.Staged)
+	require.NotEmpty(t, info.String())
+
+	require.NotEmpty(t, info.Version, "version of library isn't detected")
+	require.True(t, info.AtLeast("v0.1"))
+	require.True(t, info.AtLeast("Git info:\n    Branch: tags/v0.1.0\n"))
+	require.False(t, info.AtLeast("v999.0"))
+
+	require.False(t, info.AtLeast("unknown"))
+}
+
+func TestOpenLibrary(t *testing.T) {
+	defaultLib, err := cb.DefaultLibrary()
+	require.NoError(t, err)
+
+	lib, err := cb.OpenLibrary(defaultLib.Path())
+	require.NoError(t, err)
+	require.Same(t, defaultLib, lib)
+
+	model, err := lib.LoadFullModelFromFile(testModelPathRegressor)
+	require.NoError(t, err)
+	defer model.Close()
+	require.Same(t, lib, model.Library())
+
+	preds, err := model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625}, preds)
+
+	_, err = cb.OpenLibrary("/not/found/libcatboostmodel.so")
+	require.ErrorIs(t, err, cb.ErrNotFoundLibrary)
+}
+
+func TestLibraryNotFoundError(t *testing.T) {
+	err := error(&cb.LibraryNotFoundError{Candidates: []cb.LibraryCandidate{
+		{Path: "/usr/local/lib/libcatboostmodel.so", Err: os.ErrNotExist},
+		{Path: "/opt/lib/libcatboostmodel.so.1", Err: cb.ErrLoadLibrary},
+	}})
+
+	require.ErrorIs(t, err, cb.ErrNotFoundLibrary)
+	require.Contains(t, err.Error(), "/usr/local/lib/libcatboostmodel.so: file does not exist")
+	require.Contains(t, err.Error(), "/opt/lib/libcatboostmodel.so.1: failed loading CatBoost shared library")
+}
+
+func TestLoadFullModelFromMmap(t *testing.T) {
+	model, err := cb.LoadFullModelFromMmap(testModelPathRegressor)
+	require.NoError(t, err)
+	defer model.Close()
+
+	floats := [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}
+	cats := [][]string{{}, {}}
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+
+	preds, err = model.PredictWith(context.Background(), cb.PredictOptions{Type: cb.Exponent}, floats, cats)
+	require.NoError(t, err)
+	require.Len(t, preds, 2)
+}
+
+func TestLoadEmptyModel(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "empty.cbm")
+	require.NoError(t, os.WriteFile(filename, nil, 0o600))
+
+	_, err := cb.LoadFullModelFromFile(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+	require.ErrorIs(t, err, cb.ErrEmptyModel)
+
+	_, err = cb.LoadFullModelFromMmap(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+	require.ErrorIs(t, err, cb.ErrEmptyModel)
+
+	// Truncated model fails with the same error as LoadFullModelFromFile
+	regressor, err := os.ReadFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, regressor[:len(regressor)/2], 0o600))
+
+	_, err = cb.LoadFullModelFromFile(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+
+	_, err = cb.LoadFullModelFromMmap(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+
+	_, err = cb.LoadFullModelFromBuffer(nil)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromBuffer)
+	require.ErrorIs(t, err, cb.ErrEmptyModel)
+
+	// Truncated model
+	b, err := os.ReadFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, b[:len(b)/2], 0o600))
+
+	_, err = cb.LoadFullModelFromFile(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+
+	_, err = cb.LoadFullModelFromMmap(filename)
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromBuffer)
+}
+
+func TestLoadFullModelFromFS(t *testing.T) {
+	raw, err := os.ReadFile(testModelPathRegressor)
+	require.NoError(t, err)
+
+	var gz bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	_, err = gw.Write(raw)
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	zw, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zst := zw.EncodeAll(raw, nil)
+	require.NoError(t, zw.Close())
+
+	// Checksum is of uncompressed model for any artifact
+	sum := sha256.Sum256(raw)
+	rawSHA256 := hex.EncodeToString(sum[:])
+	gzSum := sha256.Sum256(gz.Bytes())
+
+	fsys := fstest.MapFS{
+		"models/regressor.cbm":            {Data: raw},
+		"models/regressor.cbm.gz":         {Data: gz.Bytes()},
+		"models/regressor.cbm.gz.sha256":  {Data: []byte(rawSHA256 + "  regressor.cbm\n")},
+		"models/regressor.cbm.zst":        {Data: zst},
+		"models/regressor.cbm.zst.sha256": {Data: []byte(rawSHA256 + "  regressor.cbm\n")},
+		"models/broken.cbm.gz":            {Data: gz.Bytes()},
+		"models/broken.cbm.gz.sha256":     {Data: []byte(strings.Repeat("0", 64))},
+		"models/stored.cbm.gz":            {Data: gz.Bytes()},
+		"models/stored.cbm.gz.sha256":     {Data: []byte(hex.EncodeToString(gzSum[:]) + "  stored.cbm.gz\n")},
+	}
+
+	floats := [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}
+	cats := [][]string{{}, {}}
+
+	for _, name := range []string{"models/regressor.cbm", "models/regressor.cbm.gz", "models/regressor.cbm.zst"} {
+		t.Run(name, func(t *testing.T) {
+			model, err := cb.LoadFullModelFromFS(fsys, name)
+			require.NoError(t, err)
+			defer model.Close()
+
+			preds, err := model.Predict(floats, cats)
+			require.NoError(t, err)
+			require.Equal(t, []float64{15.625, 18.125}, preds)
+
+			require.Equal(t, rawSHA256, model.Fingerprint().SHA256)
+			require.NoError(t, model.Verify(cb.LoadOptions{ExpectedSHA256: rawSHA256}))
+		})
+	}
+
+	_, err = cb.LoadFullModelFromFS(fsys, "models/broken.cbm.gz")
+	require.ErrorIs(t, err, cb.ErrChecksumMismatch)
+
+	// Checksum of compressed artifact doesn't match
+	_, err = cb.LoadFullModelFromFS(fsys, "models/stored.cbm.gz")
+	require.ErrorIs(t, err, cb.ErrChecksumMismatch)
+
+	_, err = cb.LoadFullModelFromFS(fsys, "models/missing.cbm")
+	require.ErrorIs(t, err, cb.ErrLoadFullModelFromFile)
+
+	model, err := cb.LoadFullModelFromReader(bytes.NewReader(zst))
+	require.NoError(t, err)
+	defer model.Close()
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+}
+
+func TestReloadableModel(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.cbm")
+
+	regressor, err := os.ReadFile(testModelPathRegressor)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, regressor, 0o600))
+
+	events := make(chan cb.ReloadEvent, 16)
+	model, err := cb.NewReloadableModel(filename, cb.ReloadOptions{
+		Interval: 10 * time.Millisecond,
+		OnReload: func(event cb.ReloadEvent) { events <- event },
+	})
+	require.NoError(t, err)
+	defer model.Close()
+
+	floats := [][]float32{{2, 4, 6, 8}, {1, 4, 50, 60}}
+	cats := [][]string{{}, {}}
+
+	// Predict during reloads, run with -race
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				preds, err := model.Predict(floats, cats)
+				assert.NoError(t, err)
+				assert.Equal(t, []float64{15.625, 18.125}, preds)
+			}
+		}()
+	}
+
+	// The same model with new modification time is swapped
+	for i := 0; i < 3; i++ {
+		modTime := time.Now().Add(time.Duration(i+1) * time.Second)
+		require.NoError(t, os.Chtimes(filename, modTime, modTime))
+
+		event := <-events
+		require.NoError(t, event.Err)
+		require.Equal(t, filename, event.Filename)
+	}
+
+	close(stop)
+	wg.Wait()
+
+	// Model with another schema is rejected
+	classifier, err := os.ReadFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, classifier, 0o600))
+
+	event := <-events
+	require.ErrorIs(t, event.Err, cb.ErrSchemaMismatch)
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625, 18.125}, preds)
+
+	require.NoError(t, model.Close())
+
+	_, err = model.Predict(floats, cats)
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+}
+
+func TestReloadableModelConfigure(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "model.cbm")
+
+	classifier, err := os.ReadFile(testModelPathClassifier)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filename, classifier, 0o600))
+
+	expected, err := cb.LoadFullModelFromFile(filename)
+	require.NoError(t, err)
+	defer expected.Close()
+	require.NoError(t, expected.SetPredictionType(cb.Probablity))
+
+	model, err := cb.NewReloadableModel(filename, cb.ReloadOptions{
+		Interval:  time.Hour,
+		Configure: func(m *cb.Model) error { return m.SetPredictionType(cb.Probablity) },
+	})
+	require.NoError(t, err)
+	defer model.Close()
+
+	floats := [][]float32{{2, 4, 6, 8, 5}, {1, 4, 50, 60, 5}}
+	cats := [][]string{{"a", "b"}, {"a", "d"}}
+
+	probs, err := expected.Predict(floats, cats)
+	require.NoError(t, err)
+
+	preds, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+	require.Equal(t, probs, preds)
+
+	// Prediction type is kept after reload
+	require.NoError(t, model.Reload())
+
+	preds, err = model.Predict(floats, cats)
+	require.NoError(t, err)
+	require.Equal(t, probs, preds)
+
+	// Reloaded model is rejected if it can't be configured
+	model2, err := cb.NewReloadableModel(filename, cb.ReloadOptions{
+		Interval:  time.Hour,
+		Configure: func(m *cb.Model) error { return m.SetPredictionType("Fake") },
+	})
+	require.ErrorIs(t, err, cb.ErrSetPredictionType)
+	require.Nil(t, model2)
+}
+
+func TestRegistry(t *testing.T) {
+	dir := t.TempDir()
+
+	copyModel := func(src, name, version string) {
+		b, err := os.ReadFile(src)
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, name, version), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name, version, "model.cbm"), b, 0o600))
+	}
+
+	copyModel(testModelPathRegressor, "regressor", "v2")
+	copyModel(testModelPathRegressor, "regressor", "v10")
+	copyModel(testModelPathMetadata, "metadata", "1")
+
+	registry, err := cb.OpenRegistry(dir, cb.RegistryOptions{})
+	require.NoError(t, err)
+	defer registry.Close()
+
+	require.Len(t, registry.List(), 3)
+
+	info, err := registry.Info("regressor")
+	require.NoError(t, err)
+	require.Equal(t, "v10", info.Version)
+
+	info, err = registry.Info("metadata@latest")
+	require.NoError(t, err)
+	require.Equal(t, "1", info.Version)
+	require.NotEmpty(t, info.GUID)
+	require.False(t, info.TrainFinishTime.IsZero())
+
+	require.NoError(t, registry.SetAlias("regressor", "prod", "v2"))
+	require.ErrorIs(t, registry.SetAlias("regressor", "canary", "v3"), cb.ErrModelNotFound)
+	require.ErrorIs(t, registry.SetAlias("regressor", cb.Latest, "v2"), cb.ErrInvalidAlias)
+
+	info, err = registry.Info("regressor@prod")
+	require.NoError(t, err)
+	require.Equal(t, "v2", info.Version)
+
+	model, err := registry.Get("regressor@v10")
+	require.NoError(t, err)
+
+	preds, err := model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625}, preds)
+
+	_, err = registry.Get("unknown")
+	require.ErrorIs(t, err, cb.ErrModelNotFound)
+
+	// Removed version is evicted and closed
+	require.NoError(t, os.RemoveAll(filepath.Join(dir, "regressor", "v10")))
+	require.NoError(t, registry.Refresh())
+
+	info, err = registry.Info("regressor")
+	require.NoError(t, err)
+	require.Equal(t, "v2", info.Version)
+
+	_, err = model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+	require.ErrorIs(t, err, cb.ErrModelClosed)
+
+	// Do resolves reference again if model is evicted during the call
+	copyModel(testModelPathRegressor, "regressor", "v11")
+	require.NoError(t, registry.Refresh())
+
+	var calls int
+	err = registry.Do("regressor@latest", func(model *cb.Model) error {
+		calls++
+		if calls == 1 {
+			require.NoError(t, registry.Evict("regressor", "v11"))
+		}
+
+		preds, err = model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, []float64{15.625}, preds)
+
+	err = registry.Do("unknown", func(*cb.Model) error { return nil })
+	require.ErrorIs(t, err, cb.ErrModelNotFound)
+
+	require.NoError(t, registry.Close())
+	require.ErrorIs(t, registry.Refresh(), cb.ErrRegistryClosed)
+}
+
+func TestFingerprint(t *testing.T) {
+	b, err := os.ReadFile(testModelPathMetadata)
+	require.NoError(t, err)
+
+	sum := sha256.Sum256(b)
+	expectedSHA256 := hex.EncodeToString(sum[:])
+
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	fingerprint := model.Fingerprint()
+	require.Equal(t, expectedSHA256, fingerprint.SHA256)
+	require.Equal(t, model.GetModelInfoValue(cb.MetaModelGUID), fingerprint.GUID)
+	require.NotEmpty(t, fingerprint.GUID)
+
+	modelBuffer, err := cb.LoadFullModelFromBuffer(b)
+	require.NoError(t, err)
+	defer modelBuffer.Close()
+
+	require.Equal(t, fingerprint, modelBuffer.Fingerprint())
+
+	names, err := model.GetModelUsedFeaturesNames()
+	require.NoError(t, err)
+
+	opts := cb.LoadOptions{
+		ExpectedSHA256:       strings.ToUpper(expectedSHA256),
+		ExpectedGUID:         fingerprint.GUID,
+		ExpectedFeatureNames: names,
+	}
+
+	modelWith, err := cb.LoadFullModelFromFileWith(testModelPathMetadata, opts)
+	require.NoError(t, err)
+	require.NoError(t, modelWith.Close())
+
+	_, err = cb.LoadFullModelFromBufferWith(b, cb.LoadOptions{ExpectedSHA256: hex.EncodeToString(make([]byte, 32))})
+	require.ErrorIs(t, err, cb.ErrChecksumMismatch)
+
+	_, err = cb.LoadFullModelFromFileWith(testModelPathMetadata, cb.LoadOptions{ExpectedGUID: "unknown"})
+	require.ErrorIs(t, err, cb.ErrGUIDMismatch)
+
+	_, err = cb.LoadFullModelFromFileWith(testModelPathMetadata, cb.LoadOptions{ExpectedFeatureNames: []string{"a"}})
+	require.ErrorIs(t, err, cb.ErrSchemaMismatch)
+}
+
+func TestPredictRecords(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	record := map[string]any{
+		"Column=0": 0.1, "Column=1": float32(0.2), "Column=2": 3, "Column=3": int64(4), "Column=4": uint8(5),
+		"Column=5": 0.6, "Column=6": 0.7, "Column=7": 0.8, "Column=8": 0.9, "Column=9": 1.0,
+		"CatColumn_1": "a", "CatColumn_2": 7,
+	}
+
+	preds, err := model.PredictRecords([]map[string]any{record})
+	require.NoError(t, err)
+
+	expected, err := model.Predict(
+		[][]float32{{0.1, 0.2, 3, 4, 5, 0.6, 0.7, 0.8, 0.9, 1.0}},
+		[][]string{{"a", "7"}},
+	)
+	require.NoError(t, err)
+	require.Equal(t, expected, preds)
+
+	preds, err = model.PredictRecords(nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	invalid := maps.Clone(record)
+	delete(invalid, "Column=3")
+	invalid["Column=10"] = 1.0
+
+	_, err = model.PredictRecords([]map[string]any{record, invalid})
+	require.ErrorIs(t, err, cb.ErrInvalidRecord)
+
+	var recordErr *cb.RecordError
+	require.ErrorAs(t, err, &recordErr)
+	require.Equal(t, 1, recordErr.Row)
+	require.Equal(t, []string{"Column=3"}, recordErr.Missing)
+	require.Equal(t, []string{"Column=10"}, recordErr.Unknown)
+
+	invalid = maps.Clone(record)
+	invalid["Column=0"] = "0.1"
+
+	_, err = model.PredictRecords([]map[string]any{invalid})
+	require.ErrorAs(t, err, &recordErr)
+	require.Equal(t, "Column=0", recordErr.Feature)
+	require.Equal(t, cb.FloatFeature, recordErr.Kind)
+}
+
+func TestPredictRecordsText(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathText)
+	require.NoError(t, err)
+	defer model.Close()
+
+	names, err := model.GetModelUsedFeaturesNames()
+	require.NoError(t, err)
+	require.Len(t, names, 3)
+
+	// text feature has index 0, float features 1 and 2
+	preds, err := model.PredictRecords([]map[string]any{
+		{names[0]: "amazing value", names[1]: 4.6, names[2]: 100},
+		{names[0]: "poor quality", names[1]: 1.5, names[2]: 35},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []float64{1.3351632373725695, -1.2562312927248545}, preds)
+}
+
+type metadataInput struct {
+	Column0    float64 `catboost:"Column=0"`
+	Column1    float32 `catboost:"Column=1,float"`
+	Column2    int     `catboost:"Column=2"`
+	Column3    int64   `catboost:"Column=3"`
+	Column4    uint8   `catboost:"Column=4"`
+	Column5    float64 `catboost:"Column=5"`
+	Column6    float64 `catboost:"Column=6"`
+	Column7    float64 `catboost:"Column=7"`
+	Column8    float64 `catboost:"Column=8"`
+	Column9    float64 `catboost:"Column=9"`
+	CatColumn1 string  `catboost:"CatColumn_1,cat"`
+	CatColumn2 int     `catboost:"CatColumn_2"`
+	Comment    string
+}
+
+type metadataEncoder struct {
+	metadataInput
+}
+
+func (*metadataEncoder) FeatureNames() (floats, cats, texts []string) {
+	floats = []string{"Column=0", "Column=1", "Column=2", "Column=3", "Column=4",
+		"Column=5", "Column=6", "Column=7", "Column=8", "Column=9"}
+	return floats, []string{"CatColumn_1", "CatColumn_2"}, nil
+}
+
+func (r *metadataEncoder) EncodeFeatures(floats []float32, cats, _ []string) {
+	copy(floats, []float32{float32(r.Column0), r.Column1, float32(r.Column2), float32(r.Column3), float32(r.Column4),
+		float32(r.Column5), float32(r.Column6), float32(r.Column7), float32(r.Column8), float32(r.Column9)})
+	copy(cats, []string{r.CatColumn1, fmt.Sprint(r.CatColumn2)})
+}
+
+func TestPredictStructs(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	input := metadataInput{
+		Column0: 0.1, Column1: 0.2, Column2: 3, Column3: 4, Column4: 5,
+		Column5: 0.6, Column6: 0.7, Column7: 0.8, Column8: 0.9, Column9: 1.0,
+		CatColumn1: "a", CatColumn2: 7,
+	}
+
+	expected, err := model.Predict(
+		[][]float32{{0.1, 0.2, 3, 4, 5, 0.6, 0.7, 0.8, 0.9, 1.0}},
+		[][]string{{"a", "7"}},
+	)
+	require.NoError(t, err)
+
+	preds, err := cb.PredictStructs(model, []metadataInput{input})
+	require.NoError(t, err)
+	require.Equal(t, expected, preds)
+
+	preds, err = cb.PredictStructs(model, []*metadataInput{&input})
+	require.NoError(t, err)
+	require.Equal(t, expected, preds)
+
+	preds, err = cb.PredictStructs(model, []metadataEncoder{{input}})
+	require.NoError(t, err)
+	require.Equal(t, expected, preds)
+
+	_, err = cb.PredictStructs(model, []*metadataInput{nil})
+	require.ErrorIs(t, err, cb.ErrInvalidRecord)
+
+	type partialInput struct {
+		Column0 float64 `catboost:"Column=0"`
+	}
+
+	_, err = cb.PredictStructs(model, []partialInput{{}})
+	require.ErrorIs(t, err, cb.ErrSchemaMismatch)
+
+	type wrongKindInput struct {
+		metadataInput
+		Text string `catboost:"CatColumn_1,text"`
+	}
+
+	_, err = cb.PredictStructs(model, []wrongKindInput{{}})
+	require.ErrorIs(t, err, cb.ErrSchemaMismatch)
+}
+
+func TestSchema(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	schema, err := model.Schema()
+	require.NoError(t, err)
+	require.Len(t, schema.Features, 12)
+	require.Equal(t, cb.FeatureSchema{Name: "Column=0", Kind: cb.FloatFeature, Index: 0}, schema.Features[0])
+	require.Equal(t, cb.FeatureSchema{Name: "CatColumn_2", Kind: cb.CatFeature, Index: 11}, schema.Features[11])
+	require.Equal(t, []string{"CatColumn_1", "CatColumn_2"}, schema.Names(cb.CatFeature))
+	require.Equal(t, 1, schema.Dimensions)
+	require.Empty(t, schema.ClassLabels)
+
+	b, err := json.Marshal(schema.Features[11])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"CatColumn_2","kind":"cat","index":11}`, string(b))
+
+	modelClassifier, err := cb.LoadFullModelFromFile(testModelPathClassifier)
+	require.NoError(t, err)
+	defer modelClassifier.Close()
+
+	schema, err = modelClassifier.Schema()
+	require.NoError(t, err)
+	require.Equal(t, cb.CatFeature, schema.Features[0].Kind)
+	require.Equal(t, cb.FloatFeature, schema.Features[2].Kind)
+	require.Equal(t, []string{"-1", "1"}, schema.ClassLabels)
+
+	modelMulticlass, err := cb.LoadFullModelFromFile(testModelPathMulticlassification)
+	require.NoError(t, err)
+	defer modelMulticlass.Close()
+
+	schema, err = modelMulticlass.Schema()
+	require.NoError(t, err)
+	require.Equal(t, 3, schema.Dimensions)
+	require.Equal(t, []string{"France", "UK", "USA"}, schema.ClassLabels)
+
+	b, err = json.Marshal(schema)
+	require.NoError(t, err)
+
+	var decoded cb.Schema
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	require.Equal(t, *schema, decoded)
+}
+
+func TestPredictEmptyBatch(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMulticlassification)
+	require.NoError(t, err)
+	defer model.Close()
+
+	preds, err := model.Predict(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictText(nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictTextAndEmbeddings(nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictHashed(nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictHashedText(nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictHashedTextAndEmbeddings(nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClass(0, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClassText(0, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClassTextAndEmbeddings(0, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClassHashed(0, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClassHashedText(0, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	preds, err = model.PredictClassHashedTextAndEmbeddings(0, nil, nil, nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, preds)
+
+	staged, err := model.PredictStaged(nil, nil, 0, 0, 10)
+	require.NoError(t, err)
+	for _, stage := range staged {
+		require.Empty(t, stage)
+	}
+
+	staged, err = model.PredictTextStaged(nil, nil, nil, 0, 0, 10)
+	require.NoError(t, err)
+	for _, stage := range staged {
+		require.Empty(t, stage)
+	}
+}
@@ -4,6 +4,7 @@ typedef const char *(*TypeGetErrorString)(void);
 typedef ModelCalcerHandle *(*TypeModelCalcerCreate)(void);
 typedef void (*TypeModelCalcerDelete)(ModelCalcerHandle *modelHandle);
 typedef bool (*TypeLoadFullModelFromBuffer)(ModelCalcerHandle *modelHandle, const void *binaryBuffer, size_t binaryBufferSize);
+typedef bool (*TypeLoadFullModelFromFile)(ModelCalcerHandle *modelHandle, const char *filename);
 typedef bool (*TypeCalcModelPredictionSingle)(ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
 typedef bool (*TypeCalcModelPrediction)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
 typedef bool (*TypeCalcModelPredictionText)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
@@ -19,214 +20,485 @@ typedef bool (*TypeGetFloatFeatureIndices)(ModelCalcerHandle *modelHandle, size_
 typedef bool (*TypeGetTextFeatureIndices)(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
 typedef bool (*TypeGetSupportedEvaluatorTypes)(ModelCalcerHandle *modelHandle, size_t **formulaEvaluatorTypes, size_t *count);
 typedef bool (*TypeEnableGPUEvaluation)(ModelCalcerHandle *modelHandle, int deviceId);
+typedef size_t (*TypeGetEmbeddingFeaturesCount)(ModelCalcerHandle *modelHandle);
+typedef bool (*TypeGetEmbeddingFeatureIndices)(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
+typedef bool (*TypeCalcModelPredictionTextAndEmbeddings)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize);
+typedef int (*TypeGetStringCatFeatureHash)(const char *data, size_t size);
+typedef int (*TypeGetIntegerCatFeatureHash)(long long val);
+typedef bool (*TypeCalcModelPredictionWithHashedCatFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionWithHashedCatFeaturesAndTextFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClass)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassSingle)(ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassText)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassTextAndEmbeddings)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassWithHashedCatFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassWithHashedCatFeaturesAndTextFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypePredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionFlat)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionFlatTransposed)(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize);
+typedef size_t (*TypeGetTreeCount)(ModelCalcerHandle *modelHandle);
+typedef bool (*TypeCalcModelPredictionStaged)(ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionTextStaged)(ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
+typedef bool (*TypeCalcModelPredictionSingleStaged)(ModelCalcerHandle *modelHandle, size_t treeStart, size_t treeEnd, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
 
-static TypeGetErrorString GetErrorStringFn = NULL;
-static TypeModelCalcerCreate ModelCalcerCreateFn = NULL;
-static TypeModelCalcerDelete ModelCalcerDeleteFn = NULL;
-static TypeLoadFullModelFromBuffer LoadFullModelFromBufferFn = NULL;
-static TypeCalcModelPredictionSingle CalcModelPredictionSingleFn = NULL;
-static TypeCalcModelPrediction CalcModelPredictionFn = NULL;
-static TypeCalcModelPredictionText CalcModelPredictionTextFn = NULL;
-static TypeGetFloatFeaturesCount GetFloatFeaturesCountFn = NULL;
-static TypeGetCatFeaturesCount GetCatFeaturesCountFn = NULL;
-static TypeGetTextFeaturesCount GetTextFeaturesCountFn = NULL;
-static TypeGetDimensionsCount GetDimensionsCountFn = NULL;
-static TypeSetPredictionTypeString SetPredictionTypeStringFn = NULL;
-static TypeGetModelUsedFeaturesNames GetModelUsedFeaturesNamesFn = NULL;
-static TypeGetModelInfoValue GetModelInfoValueFn = NULL;
-static TypeGetCatFeatureIndices GetCatFeatureIndicesFn = NULL;
-static TypeGetFloatFeatureIndices GetFloatFeatureIndicesFn = NULL;
-static TypeGetTextFeatureIndices GetTextFeatureIndicesFn = NULL;
-static TypeGetSupportedEvaluatorTypes GetSupportedEvaluatorTypesFn = NULL;
-static TypeEnableGPUEvaluation GetEnableGPUEvaluationFn = NULL;
+struct CatBoostLibrary
+{
+	TypeGetErrorString GetErrorStringFn;
+	TypeModelCalcerCreate ModelCalcerCreateFn;
+	TypeModelCalcerDelete ModelCalcerDeleteFn;
+	TypeLoadFullModelFromBuffer LoadFullModelFromBufferFn;
+	TypeLoadFullModelFromFile LoadFullModelFromFileFn;
+	TypeCalcModelPredictionSingle CalcModelPredictionSingleFn;
+	TypeCalcModelPrediction CalcModelPredictionFn;
+	TypeCalcModelPredictionText CalcModelPredictionTextFn;
+	TypeGetFloatFeaturesCount GetFloatFeaturesCountFn;
+	TypeGetCatFeaturesCount GetCatFeaturesCountFn;
+	TypeGetTextFeaturesCount GetTextFeaturesCountFn;
+	TypeGetDimensionsCount GetDimensionsCountFn;
+	TypeSetPredictionTypeString SetPredictionTypeStringFn;
+	TypeGetModelUsedFeaturesNames GetModelUsedFeaturesNamesFn;
+	TypeGetModelInfoValue GetModelInfoValueFn;
+	TypeGetCatFeatureIndices GetCatFeatureIndicesFn;
+	TypeGetFloatFeatureIndices GetFloatFeatureIndicesFn;
+	TypeGetTextFeatureIndices GetTextFeatureIndicesFn;
+	TypeGetSupportedEvaluatorTypes GetSupportedEvaluatorTypesFn;
+	TypeEnableGPUEvaluation GetEnableGPUEvaluationFn;
+	TypeGetEmbeddingFeaturesCount GetEmbeddingFeaturesCountFn;
+	TypeGetEmbeddingFeatureIndices GetEmbeddingFeatureIndicesFn;
+	TypeCalcModelPredictionTextAndEmbeddings CalcModelPredictionTextAndEmbeddingsFn;
+	TypeGetStringCatFeatureHash GetStringCatFeatureHashFn;
+	TypeGetIntegerCatFeatureHash GetIntegerCatFeatureHashFn;
+	TypeCalcModelPredictionWithHashedCatFeatures CalcModelPredictionWithHashedCatFeaturesFn;
+	TypeCalcModelPredictionWithHashedCatFeaturesAndTextFeatures CalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn;
+	TypeCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn;
+	TypePredictSpecificClass PredictSpecificClassFn;
+	TypePredictSpecificClassSingle PredictSpecificClassSingleFn;
+	TypePredictSpecificClassText PredictSpecificClassTextFn;
+	TypePredictSpecificClassTextAndEmbeddings PredictSpecificClassTextAndEmbeddingsFn;
+	TypePredictSpecificClassWithHashedCatFeatures PredictSpecificClassWithHashedCatFeaturesFn;
+	TypePredictSpecificClassWithHashedCatFeaturesAndTextFeatures PredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn;
+	TypePredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn;
+	TypeCalcModelPredictionFlat CalcModelPredictionFlatFn;
+	TypeCalcModelPredictionFlatTransposed CalcModelPredictionFlatTransposedFn;
+	TypeGetTreeCount GetTreeCountFn;
+	TypeCalcModelPredictionStaged CalcModelPredictionStagedFn;
+	TypeCalcModelPredictionTextStaged CalcModelPredictionTextStagedFn;
+	TypeCalcModelPredictionSingleStaged CalcModelPredictionSingleStagedFn;
+};
+
+CatBoostLibrary *NewCatBoostLibrary()
+{
+	return calloc(sizeof(CatBoostLibrary), 1);
+}
+
+const char *WrapGetErrorString(CatBoostLibrary *lib)
+{
+	return lib->GetErrorStringFn();
+}
+
+ModelCalcerHandle *WrapModelCalcerCreate(CatBoostLibrary *lib)
+{
+	return lib->ModelCalcerCreateFn();
+}
+void WrapModelCalcerDelete(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
+{
+	lib->ModelCalcerDeleteFn(modelHandle);
+}
+
+bool WrapLoadFullModelFromBuffer(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const void *binaryBuffer, size_t binaryBufferSize)
+{
+	return lib->LoadFullModelFromBufferFn(modelHandle, binaryBuffer, binaryBufferSize);
+}
+
+bool WrapLoadFullModelFromFile(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *filename)
+{
+	return lib->LoadFullModelFromFileFn(modelHandle, filename);
+}
+
+bool WrapCalcModelPredictionSingle(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionSingleFn(modelHandle, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPrediction(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionText(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionTextFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionTextAndEmbeddings(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionTextAndEmbeddingsFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, embeddingFeatures, embeddingDimensions, embeddingFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionWithHashedCatFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionWithHashedCatFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionWithHashedCatFeaturesAndTextFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, embeddingFeatures, embeddingDimensions, embeddingFeaturesSize, result, resultSize);
+}
+
+bool WrapPredictSpecificClass(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize)
+{
+	return lib->PredictSpecificClassFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, classId, result, resultSize);
+}
 
-const char *WrapGetErrorString()
+bool WrapPredictSpecificClassSingle(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize)
 {
-	return GetErrorStringFn();
+	return lib->PredictSpecificClassSingleFn(modelHandle, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, classId, result, resultSize);
 }
 
-ModelCalcerHandle *WrapModelCalcerCreate()
+bool WrapPredictSpecificClassText(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize)
 {
-	return ModelCalcerCreateFn();
+	return lib->PredictSpecificClassTextFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, classId, result, resultSize);
 }
-void WrapModelCalcerDelete(ModelCalcerHandle *modelHandle)
+
+bool WrapPredictSpecificClassTextAndEmbeddings(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize)
+{
+	return lib->PredictSpecificClassTextAndEmbeddingsFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, embeddingFeatures, embeddingDimensions, embeddingFeaturesSize, classId, result, resultSize);
+}
+
+bool WrapPredictSpecificClassWithHashedCatFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize)
+{
+	return lib->PredictSpecificClassWithHashedCatFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, classId, result, resultSize);
+}
+
+bool WrapPredictSpecificClassWithHashedCatFeaturesAndTextFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize)
+{
+	return lib->PredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, classId, result, resultSize);
+}
+
+bool WrapPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize)
+{
+	return lib->PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, embeddingFeatures, embeddingDimensions, embeddingFeaturesSize, classId, result, resultSize);
+}
+
+bool WrapCalcModelPredictionFlat(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionFlatFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionFlatTransposed(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionFlatTransposedFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionStagedFn(modelHandle, docCount, treeStart, treeEnd, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionTextStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize)
+{
+	return lib->CalcModelPredictionTextStagedFn(modelHandle, docCount, treeStart, treeEnd, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, result, resultSize);
+}
+
+bool WrapCalcModelPredictionSingleStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t treeStart, size_t treeEnd, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
 {
-	ModelCalcerDeleteFn(modelHandle);
+	return lib->CalcModelPredictionSingleStagedFn(modelHandle, treeStart, treeEnd, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
 }
 
-bool WrapLoadFullModelFromBuffer(ModelCalcerHandle *modelHandle, const void *binaryBuffer, size_t binaryBufferSize)
+bool WrapGetCatFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
 {
-	return LoadFullModelFromBufferFn(modelHandle, binaryBuffer, binaryBufferSize);
+	return lib->GetCatFeatureIndicesFn(modelHandle, indices, count);
 }
 
-bool WrapCalcModelPredictionSingle(ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+bool WrapGetFloatFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
 {
-	return CalcModelPredictionSingleFn(modelHandle, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+	return lib->GetFloatFeatureIndicesFn(modelHandle, indices, count);
 }
 
-bool WrapCalcModelPrediction(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize)
+bool WrapGetTextFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
 {
-	return CalcModelPredictionFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, result, resultSize);
+	return lib->GetTextFeatureIndicesFn(modelHandle, indices, count);
 }
 
-bool WrapCalcModelPredictionText(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize)
+bool WrapGetEmbeddingFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
 {
-	return CalcModelPredictionTextFn(modelHandle, docCount, floatFeatures, floatFeaturesSize, catFeatures, catFeaturesSize, textFeatures, textFeaturesSize, result, resultSize);
+	return lib->GetEmbeddingFeatureIndicesFn(modelHandle, indices, count);
 }
 
-bool WrapGetCatFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
+bool WrapGetModelUsedFeaturesNames(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, char ***featureNames, size_t *featureCount)
 {
-	return GetCatFeatureIndicesFn(modelHandle, indices, count);
+	return lib->GetModelUsedFeaturesNamesFn(modelHandle, featureNames, featureCount);
 }
 
-bool WrapGetFloatFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
+size_t WrapGetFloatFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return GetFloatFeatureIndicesFn(modelHandle, indices, count);
+	return lib->GetFloatFeaturesCountFn(modelHandle);
 }
 
-bool WrapGetTextFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count)
+size_t WrapGetCatFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return GetTextFeatureIndicesFn(modelHandle, indices, count);
+	return lib->GetCatFeaturesCountFn(modelHandle);
 }
 
-bool WrapGetModelUsedFeaturesNames(ModelCalcerHandle *modelHandle, char ***featureNames, size_t *featureCount)
+size_t WrapGetTextFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return GetModelUsedFeaturesNamesFn(modelHandle, featureNames, featureCount);
+	return lib->GetTextFeaturesCountFn(modelHandle);
 }
 
-size_t WrapGetFloatFeaturesCount(ModelCalcerHandle *modelHandle)
+int WrapGetStringCatFeatureHash(CatBoostLibrary *lib, const char *data, size_t size)
 {
-	return GetFloatFeaturesCountFn(modelHandle);
+	return lib->GetStringCatFeatureHashFn(data, size);
 }
 
-size_t WrapGetCatFeaturesCount(ModelCalcerHandle *modelHandle)
+int WrapGetIntegerCatFeatureHash(CatBoostLibrary *lib, long long val)
 {
-	return GetCatFeaturesCountFn(modelHandle);
+	return lib->GetIntegerCatFeatureHashFn(val);
 }
 
-size_t WrapGetTextFeaturesCount(ModelCalcerHandle *modelHandle)
+size_t WrapGetEmbeddingFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return GetTextFeaturesCountFn(modelHandle);
+	return lib->GetEmbeddingFeaturesCountFn(modelHandle);
 }
 
-size_t WrapGetDimensionsCount(ModelCalcerHandle *modelHandle)
+size_t WrapGetDimensionsCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return GetDimensionsCountFn(modelHandle);
+	return lib->GetDimensionsCountFn(modelHandle);
 }
 
-bool WrapSetPredictionTypeString(ModelCalcerHandle *modelHandle, const char *predictionTypeStr)
+size_t WrapGetTreeCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle)
 {
-	return SetPredictionTypeStringFn(modelHandle, predictionTypeStr);
+	return lib->GetTreeCountFn(modelHandle);
 }
 
-const char *WrapGetModelInfoValue(ModelCalcerHandle *modelHandle, const char *keyPtr, size_t keySize)
+bool WrapSetPredictionTypeString(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *predictionTypeStr)
 {
-	return GetModelInfoValueFn(modelHandle, keyPtr, keySize);
+	return lib->SetPredictionTypeStringFn(modelHandle, predictionTypeStr);
 }
 
-bool WrapGetSupportedEvaluatorTypes(ModelCalcerHandle *modelHandle, size_t **formulaEvaluatorTypes, size_t *count)
+const char *WrapGetModelInfoValue(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *keyPtr, size_t keySize)
 {
-	return GetSupportedEvaluatorTypesFn(modelHandle, formulaEvaluatorTypes, count);
+	return lib->GetModelInfoValueFn(modelHandle, keyPtr, keySize);
 }
 
-bool WrapEnableGPUEvaluation(ModelCalcerHandle *modelHandle, int deviceId)
+bool WrapGetSupportedEvaluatorTypes(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **formulaEvaluatorTypes, size_t *count)
 {
-	return GetEnableGPUEvaluationFn(modelHandle, deviceId);
+	return lib->GetSupportedEvaluatorTypesFn(modelHandle, formulaEvaluatorTypes, count);
 }
 
-void SetCalcModelPredictionSingleFn(void *fn)
+bool WrapEnableGPUEvaluation(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, int deviceId)
 {
-	CalcModelPredictionSingleFn = ((TypeCalcModelPredictionSingle)fn);
+	return lib->GetEnableGPUEvaluationFn(modelHandle, deviceId);
 }
 
-void SetModelCalcerCreateFn(void *fn)
+void SetCalcModelPredictionSingleFn(CatBoostLibrary *lib, void *fn)
 {
-	ModelCalcerCreateFn = ((TypeModelCalcerCreate)fn);
+	lib->CalcModelPredictionSingleFn = ((TypeCalcModelPredictionSingle)fn);
 }
 
-void SetLoadFullModelFromBufferFn(void *fn)
+void SetModelCalcerCreateFn(CatBoostLibrary *lib, void *fn)
 {
-	LoadFullModelFromBufferFn = ((TypeLoadFullModelFromBuffer)fn);
+	lib->ModelCalcerCreateFn = ((TypeModelCalcerCreate)fn);
 }
 
-void SetGetErrorStringFn(void *fn)
+void SetLoadFullModelFromBufferFn(CatBoostLibrary *lib, void *fn)
 {
-	GetErrorStringFn = ((TypeGetErrorString)fn);
+	lib->LoadFullModelFromBufferFn = ((TypeLoadFullModelFromBuffer)fn);
 }
 
-void SetCalcModelPredictionFn(void *fn)
+void SetLoadFullModelFromFileFn(CatBoostLibrary *lib, void *fn)
 {
-	CalcModelPredictionFn = ((TypeCalcModelPrediction)fn);
+	lib->LoadFullModelFromFileFn = ((TypeLoadFullModelFromFile)fn);
 }
 
-void SetCalcModelPredictionTextFn(void *fn)
+void SetGetErrorStringFn(CatBoostLibrary *lib, void *fn)
 {
-	CalcModelPredictionTextFn = ((TypeCalcModelPredictionText)fn);
+	lib->GetErrorStringFn = ((TypeGetErrorString)fn);
 }
 
-void SetGetFloatFeaturesCountFn(void *fn)
+void SetCalcModelPredictionFn(CatBoostLibrary *lib, void *fn)
 {
-	GetFloatFeaturesCountFn = ((TypeGetFloatFeaturesCount)fn);
+	lib->CalcModelPredictionFn = ((TypeCalcModelPrediction)fn);
 }
 
-void SetGetCatFeaturesCountFn(void *fn)
+void SetCalcModelPredictionTextFn(CatBoostLibrary *lib, void *fn)
 {
-	GetCatFeaturesCountFn = ((TypeGetCatFeaturesCount)fn);
+	lib->CalcModelPredictionTextFn = ((TypeCalcModelPredictionText)fn);
 }
 
-void SetGetTextFeaturesCountFn(void *fn)
+void SetGetFloatFeaturesCountFn(CatBoostLibrary *lib, void *fn)
 {
-	GetTextFeaturesCountFn = ((TypeGetTextFeaturesCount)fn);
+	lib->GetFloatFeaturesCountFn = ((TypeGetFloatFeaturesCount)fn);
 }
 
-void SetGetCatFeatureIndicesFn(void *fn)
+void SetGetCatFeaturesCountFn(CatBoostLibrary *lib, void *fn)
 {
-	GetCatFeatureIndicesFn = ((TypeGetCatFeatureIndices)fn);
+	lib->GetCatFeaturesCountFn = ((TypeGetCatFeaturesCount)fn);
 }
 
-void SetGetFloatFeatureIndicesFn(void *fn)
+void SetGetTextFeaturesCountFn(CatBoostLibrary *lib, void *fn)
 {
-	GetFloatFeatureIndicesFn = ((TypeGetFloatFeatureIndices)fn);
+	lib->GetTextFeaturesCountFn = ((TypeGetTextFeaturesCount)fn);
 }
 
-void SetGetTextFeatureIndicesFn(void *fn)
+void SetGetCatFeatureIndicesFn(CatBoostLibrary *lib, void *fn)
 {
-	GetTextFeatureIndicesFn = ((TypeGetTextFeatureIndices)fn);
+	lib->GetCatFeatureIndicesFn = ((TypeGetCatFeatureIndices)fn);
 }
 
-void SetGetDimensionsCountFn(void *fn)
+void SetGetFloatFeatureIndicesFn(CatBoostLibrary *lib, void *fn)
 {
-	GetDimensionsCountFn = ((TypeGetDimensionsCount)fn);
+	lib->GetFloatFeatureIndicesFn = ((TypeGetFloatFeatureIndices)fn);
 }
 
-void SetSetPredictionTypeStringFn(void *fn)
+void SetGetTextFeatureIndicesFn(CatBoostLibrary *lib, void *fn)
 {
-	SetPredictionTypeStringFn = ((TypeSetPredictionTypeString)fn);
+	lib->GetTextFeatureIndicesFn = ((TypeGetTextFeatureIndices)fn);
 }
 
-void SetGetModelUsedFeaturesNamesFn(void *fn)
+void SetGetDimensionsCountFn(CatBoostLibrary *lib, void *fn)
 {
-	GetModelUsedFeaturesNamesFn = ((TypeGetModelUsedFeaturesNames)fn);
+	lib->GetDimensionsCountFn = ((TypeGetDimensionsCount)fn);
 }
 
-void SetGetModelInfoValueFn(void *fn)
+void SetSetPredictionTypeStringFn(CatBoostLibrary *lib, void *fn)
 {
-	GetModelInfoValueFn = ((TypeGetModelInfoValue)fn);
+	lib->SetPredictionTypeStringFn = ((TypeSetPredictionTypeString)fn);
 }
 
-void SetGetSupportedEvaluatorTypesFn(void *fn)
+void SetGetModelUsedFeaturesNamesFn(CatBoostLibrary *lib, void *fn)
 {
-	GetSupportedEvaluatorTypesFn = ((TypeGetSupportedEvaluatorTypes)fn);
+	lib->GetModelUsedFeaturesNamesFn = ((TypeGetModelUsedFeaturesNames)fn);
 }
 
-void SetGetEnableGPUEvaluationFn(void *fn)
+void SetGetModelInfoValueFn(CatBoostLibrary *lib, void *fn)
 {
-	GetEnableGPUEvaluationFn = ((TypeEnableGPUEvaluation)fn);
+	lib->GetModelInfoValueFn = ((TypeGetModelInfoValue)fn);
 }
 
-void SetModelCalcerDeleteFn(void *fn)
+void SetGetSupportedEvaluatorTypesFn(CatBoostLibrary *lib, void *fn)
 {
-	ModelCalcerDeleteFn = ((TypeModelCalcerDelete)fn);
+	lib->GetSupportedEvaluatorTypesFn = ((TypeGetSupportedEvaluatorTypes)fn);
+}
+
+void SetGetEnableGPUEvaluationFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetEnableGPUEvaluationFn = ((TypeEnableGPUEvaluation)fn);
+}
+
+void SetModelCalcerDeleteFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->ModelCalcerDeleteFn = ((TypeModelCalcerDelete)fn);
+}
+
+void SetGetEmbeddingFeaturesCountFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetEmbeddingFeaturesCountFn = ((TypeGetEmbeddingFeaturesCount)fn);
+}
+
+void SetGetEmbeddingFeatureIndicesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetEmbeddingFeatureIndicesFn = ((TypeGetEmbeddingFeatureIndices)fn);
+}
+
+void SetCalcModelPredictionTextAndEmbeddingsFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionTextAndEmbeddingsFn = ((TypeCalcModelPredictionTextAndEmbeddings)fn);
+}
+
+void SetGetStringCatFeatureHashFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetStringCatFeatureHashFn = ((TypeGetStringCatFeatureHash)fn);
+}
+
+void SetGetIntegerCatFeatureHashFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetIntegerCatFeatureHashFn = ((TypeGetIntegerCatFeatureHash)fn);
+}
+
+void SetCalcModelPredictionWithHashedCatFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionWithHashedCatFeaturesFn = ((TypeCalcModelPredictionWithHashedCatFeatures)fn);
+}
+
+void SetCalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn = ((TypeCalcModelPredictionWithHashedCatFeaturesAndTextFeatures)fn);
+}
+
+void SetCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn = ((TypeCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures)fn);
+}
+
+void SetPredictSpecificClassFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassFn = ((TypePredictSpecificClass)fn);
+}
+
+void SetPredictSpecificClassSingleFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassSingleFn = ((TypePredictSpecificClassSingle)fn);
+}
+
+void SetPredictSpecificClassTextFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassTextFn = ((TypePredictSpecificClassText)fn);
+}
+
+void SetPredictSpecificClassTextAndEmbeddingsFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassTextAndEmbeddingsFn = ((TypePredictSpecificClassTextAndEmbeddings)fn);
+}
+
+void SetPredictSpecificClassWithHashedCatFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassWithHashedCatFeaturesFn = ((TypePredictSpecificClassWithHashedCatFeatures)fn);
+}
+
+void SetPredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn = ((TypePredictSpecificClassWithHashedCatFeaturesAndTextFeatures)fn);
+}
+
+void SetPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn = ((TypePredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures)fn);
+}
+
+void SetCalcModelPredictionFlatFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionFlatFn = ((TypeCalcModelPredictionFlat)fn);
+}
+
+void SetCalcModelPredictionFlatTransposedFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionFlatTransposedFn = ((TypeCalcModelPredictionFlatTransposed)fn);
+}
+
+void SetGetTreeCountFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->GetTreeCountFn = ((TypeGetTreeCount)fn);
+}
+
+void SetCalcModelPredictionStagedFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionStagedFn = ((TypeCalcModelPredictionStaged)fn);
+}
+
+void SetCalcModelPredictionTextStagedFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionTextStagedFn = ((TypeCalcModelPredictionTextStaged)fn);
+}
+
+void SetCalcModelPredictionSingleStagedFn(CatBoostLibrary *lib, void *fn)
+{
+	lib->CalcModelPredictionSingleStagedFn = ((TypeCalcModelPredictionSingleStaged)fn);
 }
 
 char ***makeCharArray2D(int size)
@@ -247,14 +519,6 @@ void freeCharArray1D(char **a, int size)
 	free(a);
 }
 
-void freeCharArray2D(char ***a, int sizeX, int sizeY)
-{
-	int i;
-	for (i = 0; i < sizeX; i++)
-		freeCharArray1D(a[i], sizeY);
-	free(a);
-}
-
 void setCharArray1D(char **a, char *s, int n)
 {
 	a[n] = s;
@@ -274,3 +538,40 @@ void setFloatArray2D(float **a, float *f, int n)
 {
 	a[n] = f;
 }
+
+float **makeFloatArray2DFromFlat(float *data, int size, int stride)
+{
+	int i;
+	float **a = calloc(sizeof(float *), size);
+	for (i = 0; i < size; i++)
+		a[i] = data + (size_t)i * stride;
+	return a;
+}
+
+int **makeIntArray2D(int size)
+{
+	return calloc(sizeof(int *), size);
+}
+
+void setIntArray2D(int **a, int *f, int n)
+{
+	a[n] = f;
+}
+
+float ***makeFloatArray3D(int size)
+{
+	return calloc(sizeof(float **), size);
+}
+
+void setFloatArray3D(float ***a, float **f, int n)
+{
+	a[n] = f;
+}
+
+void freeFloatArray3D(float ***a, int size)
+{
+	int i;
+	for (i = 0; i < size; i++)
+		free(a[i]);
+	free(a);
+}
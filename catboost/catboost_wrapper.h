@@ -2,53 +2,107 @@
 #include <stdlib.h>
 #include "c_api.h"
 
-void SetGetErrorStringFn(void *fn);
-void SetCalcModelPredictionSingleFn(void *fn);
-void SetModelCalcerCreateFn(void *fn);
-void SetModelCalcerDeleteFn(void *fn);
-void SetLoadFullModelFromBufferFn(void *fn);
-void SetCalcModelPredictionFn(void *fn);
-void SetCalcModelPredictionTextFn(void *fn);
-void SetGetFloatFeaturesCountFn(void *fn);
-void SetGetCatFeaturesCountFn(void *fn);
-void SetGetTextFeaturesCountFn(void *fn);
-void SetGetDimensionsCountFn(void *fn);
-void SetSetPredictionTypeStringFn(void *fn);
-void SetGetModelUsedFeaturesNamesFn(void *fn);
-void SetGetModelInfoValueFn(void *fn);
-void SetGetCatFeatureIndicesFn(void *fn);
-void SetGetFloatFeatureIndicesFn(void *fn);
-void SetGetTextFeatureIndicesFn(void *fn);
-void SetGetSupportedEvaluatorTypesFn(void *fn);
-void SetGetEnableGPUEvaluationFn(void *fn);
+// CatBoostLibrary is a table of functions loaded from CatBoost shared library.
+typedef struct CatBoostLibrary CatBoostLibrary;
 
-const char *WrapGetErrorString();
-ModelCalcerHandle *WrapModelCalcerCreate();
-void WrapModelCalcerDelete(ModelCalcerHandle *modelHandle);
-bool WrapLoadFullModelFromBuffer(ModelCalcerHandle *modelHandle, const void *binaryBuffer, size_t binaryBufferSize);
-bool WrapCalcModelPredictionSingle(ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
-bool WrapCalcModelPrediction(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
-bool WrapCalcModelPredictionText(ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
-size_t WrapGetFloatFeaturesCount(ModelCalcerHandle *modelHandle);
-size_t WrapGetCatFeaturesCount(ModelCalcerHandle *modelHandle);
-size_t WrapGetTextFeaturesCount(ModelCalcerHandle *modelHandle);
-size_t WrapGetDimensionsCount(ModelCalcerHandle *modelHandle);
-bool WrapSetPredictionTypeString(ModelCalcerHandle *modelHandle, const char *predictionTypeStr);
-bool WrapGetModelUsedFeaturesNames(ModelCalcerHandle *modelHandle, char ***featureNames, size_t *featureCount);
-const char *WrapGetModelInfoValue(ModelCalcerHandle *modelHandle, const char *keyPtr, size_t keySize);
-bool WrapGetCatFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
-bool WrapGetFloatFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
-bool WrapGetTextFeatureIndices(ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
-bool WrapGetSupportedEvaluatorTypes(ModelCalcerHandle *modelHandle, size_t **formulaEvaluatorTypes, size_t *count);
-bool WrapEnableGPUEvaluation(ModelCalcerHandle *modelHandle, int deviceId);
+CatBoostLibrary *NewCatBoostLibrary();
+
+void SetGetErrorStringFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionSingleFn(CatBoostLibrary *lib, void *fn);
+void SetModelCalcerCreateFn(CatBoostLibrary *lib, void *fn);
+void SetModelCalcerDeleteFn(CatBoostLibrary *lib, void *fn);
+void SetLoadFullModelFromBufferFn(CatBoostLibrary *lib, void *fn);
+void SetLoadFullModelFromFileFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionTextFn(CatBoostLibrary *lib, void *fn);
+void SetGetFloatFeaturesCountFn(CatBoostLibrary *lib, void *fn);
+void SetGetCatFeaturesCountFn(CatBoostLibrary *lib, void *fn);
+void SetGetTextFeaturesCountFn(CatBoostLibrary *lib, void *fn);
+void SetGetDimensionsCountFn(CatBoostLibrary *lib, void *fn);
+void SetSetPredictionTypeStringFn(CatBoostLibrary *lib, void *fn);
+void SetGetModelUsedFeaturesNamesFn(CatBoostLibrary *lib, void *fn);
+void SetGetModelInfoValueFn(CatBoostLibrary *lib, void *fn);
+void SetGetCatFeatureIndicesFn(CatBoostLibrary *lib, void *fn);
+void SetGetFloatFeatureIndicesFn(CatBoostLibrary *lib, void *fn);
+void SetGetTextFeatureIndicesFn(CatBoostLibrary *lib, void *fn);
+void SetGetSupportedEvaluatorTypesFn(CatBoostLibrary *lib, void *fn);
+void SetGetEnableGPUEvaluationFn(CatBoostLibrary *lib, void *fn);
+void SetGetEmbeddingFeaturesCountFn(CatBoostLibrary *lib, void *fn);
+void SetGetEmbeddingFeatureIndicesFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionTextAndEmbeddingsFn(CatBoostLibrary *lib, void *fn);
+void SetGetStringCatFeatureHashFn(CatBoostLibrary *lib, void *fn);
+void SetGetIntegerCatFeatureHashFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionWithHashedCatFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassSingleFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassTextFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassTextAndEmbeddingsFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassWithHashedCatFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionFlatFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionFlatTransposedFn(CatBoostLibrary *lib, void *fn);
+void SetGetTreeCountFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionStagedFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionTextStagedFn(CatBoostLibrary *lib, void *fn);
+void SetCalcModelPredictionSingleStagedFn(CatBoostLibrary *lib, void *fn);
+
+const char *WrapGetErrorString(CatBoostLibrary *lib);
+ModelCalcerHandle *WrapModelCalcerCreate(CatBoostLibrary *lib);
+void WrapModelCalcerDelete(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+bool WrapLoadFullModelFromFile(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *filename);
+bool WrapLoadFullModelFromBuffer(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const void *binaryBuffer, size_t binaryBufferSize);
+bool WrapCalcModelPredictionSingle(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPrediction(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionText(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
+size_t WrapGetFloatFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+size_t WrapGetCatFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+size_t WrapGetTextFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+size_t WrapGetDimensionsCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+bool WrapSetPredictionTypeString(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *predictionTypeStr);
+bool WrapGetModelUsedFeaturesNames(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, char ***featureNames, size_t *featureCount);
+const char *WrapGetModelInfoValue(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const char *keyPtr, size_t keySize);
+bool WrapGetCatFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
+bool WrapGetFloatFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
+bool WrapGetTextFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
+bool WrapGetSupportedEvaluatorTypes(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **formulaEvaluatorTypes, size_t *count);
+bool WrapEnableGPUEvaluation(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, int deviceId);
+size_t WrapGetEmbeddingFeaturesCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+bool WrapGetEmbeddingFeatureIndices(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t **indices, size_t *count);
+bool WrapCalcModelPredictionTextAndEmbeddings(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize);
+int WrapGetStringCatFeatureHash(CatBoostLibrary *lib, const char *data, size_t size);
+int WrapGetIntegerCatFeatureHash(CatBoostLibrary *lib, long long val);
+bool WrapCalcModelPredictionWithHashedCatFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionWithHashedCatFeaturesAndTextFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, double *result, size_t resultSize);
+bool WrapPredictSpecificClass(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassSingle(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassText(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassTextAndEmbeddings(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassWithHashedCatFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassWithHashedCatFeaturesAndTextFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, const int **catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, const float ***embeddingFeatures, size_t *embeddingDimensions, size_t embeddingFeaturesSize, int classId, double *result, size_t resultSize);
+bool WrapCalcModelPredictionFlat(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionFlatTransposed(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, const float **floatFeatures, size_t floatFeaturesSize, double *result, size_t resultSize);
+size_t WrapGetTreeCount(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle);
+bool WrapCalcModelPredictionStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionTextStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t docCount, size_t treeStart, size_t treeEnd, const float **floatFeatures, size_t floatFeaturesSize, const char ***catFeatures, size_t catFeaturesSize, const char ***textFeatures, size_t textFeaturesSize, double *result, size_t resultSize);
+bool WrapCalcModelPredictionSingleStaged(CatBoostLibrary *lib, ModelCalcerHandle *modelHandle, size_t treeStart, size_t treeEnd, const float *floatFeatures, size_t floatFeaturesSize, const char **catFeatures, size_t catFeaturesSize, double *result, size_t resultSize);
 
 void freeCharArray1D(char **a, int size);
-void freeCharArray2D(char ***a, int sizeX, int sizeY);
+void freeFloatArray3D(float ***a, int size);
 
 void setCharArray1D(char **a, char *s, int n);
 void setFloatArray2D(float **a, float *f, int n);
 void setCharArray2D(char ***a, char **s, int n);
+void setFloatArray3D(float ***a, float **f, int n);
+void setIntArray2D(int **a, int *f, int n);
 
 char **makeCharArray1D(int size);
 char ***makeCharArray2D(int size);
 float **makeFloatArray2D(int size);
+float ***makeFloatArray3D(int size);
+float **makeFloatArray2DFromFlat(float *data, int size, int stride);
+int **makeIntArray2D(int size);
@@ -0,0 +1,502 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// PredictClass returns predictions for specific class of multiclass model.
+// Result contains one value per sample.
+func (m *Model) PredictClass(classID int, floats [][]float32, cats [][]string) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClass"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(cats))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	if !C.WrapPredictSpecificClass(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("PredictSpecificClass", ErrPredictSpecificClass, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictSingleClass returns prediction for specific class of multiclass model for a single sample.
+func (m *Model) PredictSingleClass(classID int, floats []float32, cats []string) (float64, error) {
+	if err := m.acquire(); err != nil {
+		return 0, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassSingle"); err != nil {
+		return 0, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return 0, err
+	}
+
+	if err := m.checkInput(
+		checkRow(FloatFeature, floats, m.floatFeaturesCount()),
+		checkRow(CatFeature, cats, m.catFeaturesCount()),
+	); err != nil {
+		return 0, err
+	}
+
+	catsC := makeCharArray1D(cats)
+	defer C.freeCharArray1D(catsC, C.int(len(cats)))
+
+	floatsC := new(C.float)
+	if len(floats) > 0 {
+		floatsC = (*C.float)(&floats[0])
+	}
+
+	var pred float64
+
+	if !C.WrapPredictSpecificClassSingle(
+		m.lib.fns,
+		m.handler,
+		floatsC,
+		C.size_t(len(floats)),
+		catsC,
+		C.size_t(len(cats)),
+		C.int(classID),
+		(*C.double)(&pred),
+		1) {
+		return 0, m.lib.newError("PredictSpecificClassSingle", ErrPredictSpecificClass, &Shape{
+			Samples: 1, FloatFeatures: len(floats), CatFeatures: len(cats),
+		})
+	}
+
+	return pred, nil
+}
+
+// PredictClassText returns predictions for specific class of multiclass model
+// for samples with text features.
+func (m *Model) PredictClassText(
+	classID int, floats [][]float32, cats [][]string, texts [][]string,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassText"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(cats), len(texts))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	if !C.WrapPredictSpecificClassText(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("PredictSpecificClassText", ErrPredictSpecificClass, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount, TextFeatures: textFeaturesCount,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictClassTextAndEmbeddings returns predictions for specific class of multiclass model
+// for samples with text and embedding features.
+//
+//nolint:funlen
+func (m *Model) PredictClassTextAndEmbeddings(
+	classID int, floats [][]float32, cats [][]string, texts [][]string, embeddings [][][]float32,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassTextAndEmbeddings"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(cats), len(texts), len(embeddings))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+	embeddingFeaturesCount := m.embeddingFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, cats, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	dimensions, err := getEmbeddingDimensions(embeddings, nSamples, embeddingFeaturesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeCharArray2D(cats)
+	defer freeCharArray2D(catsC, cats)
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	embeddingsC := makeFloatArray3D(embeddings)
+	defer C.freeFloatArray3D(embeddingsC, C.int(len(embeddings)))
+
+	if !C.WrapPredictSpecificClassTextAndEmbeddings(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		embeddingsC,
+		getSizePointer(dimensions),
+		C.size_t(embeddingFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("PredictSpecificClassTextAndEmbeddings", ErrPredictSpecificClass, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+			TextFeatures: textFeaturesCount, EmbeddingFeatures: embeddingFeaturesCount,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictClassHashed returns predictions for specific class of multiclass model
+// for samples with hashed categorical features.
+func (m *Model) PredictClassHashed(classID int, floats [][]float32, catHashes [][]int32) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassWithHashedCatFeatures"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(catHashes))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	if !C.WrapPredictSpecificClassWithHashedCatFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("PredictSpecificClassWithHashedCatFeatures", ErrPredictSpecificClass, &Shape{
+			Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictClassHashedText returns predictions for specific class of multiclass model
+// for samples with hashed categorical and text features.
+func (m *Model) PredictClassHashedText(
+	classID int, floats [][]float32, catHashes [][]int32, texts [][]string,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassWithHashedCatFeaturesAndTextFeatures"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(catHashes), len(texts))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	if !C.WrapPredictSpecificClassWithHashedCatFeaturesAndTextFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError(
+			"PredictSpecificClassWithHashedCatFeaturesAndTextFeatures", ErrPredictSpecificClass, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+				TextFeatures: textFeaturesCount,
+			},
+		)
+	}
+
+	return preds, nil
+}
+
+// PredictClassHashedTextAndEmbeddings returns predictions for specific class of multiclass model
+// for samples with hashed categorical, text and embedding features.
+//
+//nolint:funlen
+func (m *Model) PredictClassHashedTextAndEmbeddings(
+	classID int, floats [][]float32, catHashes [][]int32, texts [][]string, embeddings [][][]float32,
+) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures"); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkClassID(classID); err != nil {
+		return nil, err
+	}
+
+	nSamples := getSamplesCount(len(floats), len(catHashes), len(texts), len(embeddings))
+
+	floatFeaturesCount := m.floatFeaturesCount()
+	catFeaturesCount := m.catFeaturesCount()
+	textFeaturesCount := m.textFeaturesCount()
+	embeddingFeaturesCount := m.embeddingFeaturesCount()
+
+	if err := m.checkInput(
+		checkRows(FloatFeature, floats, nSamples, floatFeaturesCount),
+		checkRows(CatFeature, catHashes, nSamples, catFeaturesCount),
+		checkRows(TextFeature, texts, nSamples, textFeaturesCount),
+	); err != nil {
+		return nil, err
+	}
+
+	if nSamples == 0 {
+		return []float64{}, nil
+	}
+
+	dimensions, err := getEmbeddingDimensions(embeddings, nSamples, embeddingFeaturesCount)
+	if err != nil {
+		return nil, err
+	}
+
+	preds := make([]float64, nSamples)
+
+	floatsC := makeFloatArray2D(floats)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	catsC := makeIntArray2D(catHashes)
+	defer C.free(unsafe.Pointer(catsC))
+
+	textsC := makeCharArray2D(texts)
+	defer freeCharArray2D(textsC, texts)
+
+	embeddingsC := makeFloatArray3D(embeddings)
+	defer C.freeFloatArray3D(embeddingsC, C.int(len(embeddings)))
+
+	if !C.WrapPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nSamples),
+		floatsC,
+		C.size_t(floatFeaturesCount),
+		catsC,
+		C.size_t(catFeaturesCount),
+		textsC,
+		C.size_t(textFeaturesCount),
+		embeddingsC,
+		getSizePointer(dimensions),
+		C.size_t(embeddingFeaturesCount),
+		C.int(classID),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError(
+			"PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures", ErrPredictSpecificClass, &Shape{
+				Samples: nSamples, FloatFeatures: floatFeaturesCount, CatFeatures: catFeaturesCount,
+				TextFeatures: textFeaturesCount, EmbeddingFeatures: embeddingFeaturesCount,
+			},
+		)
+	}
+
+	return preds, nil
+}
+
+// checkClassID returns error if class id is out of model dimensions.
+func (m *Model) checkClassID(classID int) error {
+	dimensions := m.dimensionsCount()
+	if classID < 0 || classID >= dimensions {
+		return fmt.Errorf("%w: %d (model has %d classes)", ErrInvalidClassID, classID, dimensions)
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// openModels is number of model handles not released yet, it is checked by tests.
+var openModels atomic.Int64
+
+// newModel returns model for loaded handle.
+// Handle is released by Close or by finalizer if model is unreachable.
+func newModel(lib *Library, handler unsafe.Pointer, buffer []byte) *Model {
+	m := &Model{lib: lib, handler: handler, predictionType: RawFormulaVal, buffer: buffer}
+	runtime.SetFinalizer(m, (*Model).Close)
+	openModels.Add(1)
+
+	return m
+}
+
+// Close releases model handle. Close waits for calls in progress and is idempotent.
+// All methods return ErrModelClosed after Close.
+func (m *Model) Close() error {
+	m.stateMu.Lock()
+	if m.closed {
+		m.stateMu.Unlock()
+		return nil
+	}
+	m.closed = true
+	m.stateMu.Unlock()
+
+	m.inUse.Wait()
+
+	m.mu.Lock()
+	for _, model := range m.typed {
+		_ = model.Close()
+	}
+	m.typed = nil
+	m.mu.Unlock()
+
+	C.WrapModelCalcerDelete(m.lib.fns, m.handler)
+	m.handler = nil
+	openModels.Add(-1)
+
+	runtime.SetFinalizer(m, nil)
+
+	return nil
+}
+
+// acquire marks handle as used by call, returns ErrModelClosed if model is closed.
+// The handle is not released until release is called. The goroutine is locked to its thread
+// till release, because CatBoost stores error message of failed call per thread.
+func (m *Model) acquire() error {
+	m.stateMu.RLock()
+	defer m.stateMu.RUnlock()
+
+	if m.closed {
+		return ErrModelClosed
+	}
+	m.inUse.Add(1)
+	runtime.LockOSThread()
+
+	return nil
+}
+
+// release marks end of call using handle.
+func (m *Model) release() {
+	runtime.UnlockOSThread()
+	m.inUse.Done()
+}
+
+// Library returns library the model is loaded from.
+func (m *Model) Library() *Library {
+	return m.lib
+}
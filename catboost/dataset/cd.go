@@ -0,0 +1,143 @@
+// Package dataset scores CSV/TSV files described by CatBoost column description (.cd) files
+// in batches and writes predictions, as `catboost calc` does.
+//
+//	columns, err := dataset.LoadColumnDescription("train.cd")
+//	...
+//	n, err := dataset.Score(ctx, model, input, output, columns, dataset.Options{HasHeader: true})
+//
+// See https://catboost.ai/en/docs/concepts/input-data_column-descfile.
+package dataset
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+)
+
+// ColumnType is type of column in column description file.
+type ColumnType string
+
+const (
+	Num         ColumnType = "Num"
+	Categ       ColumnType = "Categ"
+	Text        ColumnType = "Text"
+	Embedding   ColumnType = "Embedding"
+	NumVector   ColumnType = "NumVector"
+	Label       ColumnType = "Label"
+	Auxiliary   ColumnType = "Auxiliary"
+	Baseline    ColumnType = "Baseline"
+	Weight      ColumnType = "Weight"
+	SampleID    ColumnType = "SampleId"
+	GroupID     ColumnType = "GroupId"
+	GroupWeight ColumnType = "GroupWeight"
+	SubgroupID  ColumnType = "SubgroupId"
+	Timestamp   ColumnType = "Timestamp"
+	Position    ColumnType = "Position"
+)
+
+// columnTypes are known column types with aliases of older CatBoost versions.
+var columnTypes = map[string]ColumnType{
+	"Num": Num, "Categ": Categ, "Text": Text, "Embedding": Embedding, "NumVector": NumVector,
+	"Label": Label, "Target": Label, "Auxiliary": Auxiliary, "Baseline": Baseline, "Weight": Weight,
+	"SampleId": SampleID, "DocId": SampleID, "GroupId": GroupID, "QueryId": GroupID,
+	"GroupWeight": GroupWeight, "SubgroupId": SubgroupID, "Timestamp": Timestamp, "Position": Position,
+}
+
+// ErrInvalidColumnDescription is returned for invalid column description or columns of data.
+var ErrInvalidColumnDescription = errors.New("invalid column description")
+
+// Column is column of data file, columns missing in column description are Num.
+type Column struct {
+	// Index is zero-based index of column in data file.
+	Index int
+	Type  ColumnType
+	// Name is optional name of column.
+	Name string
+}
+
+// Kind returns kind of model feature of column, "" if column isn't a feature.
+func (c Column) Kind() cb.FeatureKind {
+	switch c.Type {
+	case Num:
+		return cb.FloatFeature
+	case Categ:
+		return cb.CatFeature
+	case Text:
+		return cb.TextFeature
+	case Embedding, NumVector:
+		return cb.EmbeddingFeature
+	default:
+		return ""
+	}
+}
+
+// LoadColumnDescription returns columns of column description file.
+func LoadColumnDescription(filename string) ([]Column, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadColumnDescription(f)
+}
+
+// ReadColumnDescription returns columns of column description sorted by index.
+// Each line is tab-separated `index type [name]`, empty lines and lines starting with # are skipped.
+func ReadColumnDescription(r io.Reader) ([]Column, error) {
+	var columns []Column
+	seen := make(map[int]bool)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("%w: line %d: expected `index<TAB>type[<TAB>name]`, got %q",
+				ErrInvalidColumnDescription, line, text)
+		}
+
+		index, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil || index < 0 {
+			return nil, fmt.Errorf("%w: line %d: invalid column index %q", ErrInvalidColumnDescription, line, fields[0])
+		}
+
+		columnType, ok := columnTypes[strings.TrimSpace(fields[1])]
+		if !ok {
+			return nil, fmt.Errorf("%w: line %d: unknown column type %q", ErrInvalidColumnDescription, line, fields[1])
+		}
+
+		if seen[index] {
+			return nil, fmt.Errorf("%w: line %d: duplicate column %d", ErrInvalidColumnDescription, line, index)
+		}
+		seen[index] = true
+
+		column := Column{Index: index, Type: columnType}
+		if len(fields) == 3 {
+			column.Name = strings.TrimSpace(fields[2])
+		}
+
+		columns = append(columns, column)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Index < columns[j].Index
+	})
+
+	return columns, nil
+}
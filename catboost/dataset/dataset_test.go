@@ -0,0 +1,138 @@
+package dataset_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+	"github.com/mirecl/catboost-cgo/catboost/dataset"
+	"github.com/stretchr/testify/require"
+)
+
+const testModelPathMetadata = "../../example/metadata/metadata.cbm"
+
+func TestReadColumnDescription(t *testing.T) {
+	columns, err := dataset.ReadColumnDescription(strings.NewReader(
+		"# comment\n3\tCateg\tCity\n0\tLabel\n\n1\tSampleId\n2\tText\tReview\n",
+	))
+	require.NoError(t, err)
+	require.Equal(t, []dataset.Column{
+		{Index: 0, Type: dataset.Label},
+		{Index: 1, Type: dataset.SampleID},
+		{Index: 2, Type: dataset.Text, Name: "Review"},
+		{Index: 3, Type: dataset.Categ, Name: "City"},
+	}, columns)
+
+	_, err = dataset.ReadColumnDescription(strings.NewReader("0\tUnknown\n"))
+	require.ErrorIs(t, err, dataset.ErrInvalidColumnDescription)
+
+	_, err = dataset.ReadColumnDescription(strings.NewReader("0\tNum\n0\tCateg\n"))
+	require.ErrorIs(t, err, dataset.ErrInvalidColumnDescription)
+
+	_, err = dataset.ReadColumnDescription(strings.NewReader("x\tNum\n"))
+	require.ErrorIs(t, err, dataset.ErrInvalidColumnDescription)
+}
+
+func TestReader(t *testing.T) {
+	columns := []dataset.Column{
+		{Index: 0, Type: dataset.SampleID},
+		{Index: 2, Type: dataset.Categ},
+		{Index: 3, Type: dataset.Label},
+	}
+
+	data := "id,age,city,target,score\na,1.5,Paris,1,NaN\nb,,\"New York, NY\",0,2\nc,NA,London,1,3\n"
+
+	reader := dataset.NewReader(strings.NewReader(data), columns, dataset.Options{Delimiter: ',', HasHeader: true, BatchSize: 2})
+
+	batch, err := reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, 2, batch.Len())
+	require.Equal(t, []string{"a", "b"}, batch.SampleIDs)
+	require.Equal(t, [][]string{{"Paris"}, {"New York, NY"}}, batch.Cats)
+	require.Equal(t, float32(1.5), batch.Floats[0][0])
+	require.True(t, math.IsNaN(float64(batch.Floats[0][1])))
+	require.True(t, math.IsNaN(float64(batch.Floats[1][0])))
+
+	require.Equal(t, []dataset.Column{
+		{Index: 1, Type: dataset.Num, Name: "age"},
+		{Index: 2, Type: dataset.Categ, Name: "city"},
+		{Index: 4, Type: dataset.Num, Name: "score"},
+	}, reader.Features())
+
+	batch, err = reader.Read()
+	require.NoError(t, err)
+	require.Equal(t, []string{"c"}, batch.SampleIDs)
+	require.True(t, math.IsNaN(float64(batch.Floats[0][0])))
+	require.Equal(t, float32(3), batch.Floats[0][1])
+
+	_, err = reader.Read()
+	require.ErrorIs(t, err, io.EOF)
+
+	reader = dataset.NewReader(strings.NewReader("1\tx\n"), nil, dataset.Options{})
+	_, err = reader.Read()
+	require.ErrorContains(t, err, "invalid value of Num column")
+}
+
+func TestScore(t *testing.T) {
+	model, err := cb.LoadFullModelFromFile(testModelPathMetadata)
+	require.NoError(t, err)
+	defer model.Close()
+
+	// Label, 10 Num features, 2 Categ features
+	columns, err := dataset.ReadColumnDescription(strings.NewReader("0\tLabel\n11\tCateg\n12\tCateg\n"))
+	require.NoError(t, err)
+
+	floats := [][]float32{{0.1, 0.2, 3, 4, 5, 0.6, 0.7, 0.8, 0.9, 1.0}, {1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, {0, 0, 0, 0, 0, 0, 0, 0, 0, 0}}
+	cats := [][]string{{"a", "7"}, {"b", "8"}, {"c", "9"}}
+
+	var input strings.Builder
+	for i := range floats {
+		input.WriteString("1")
+		for _, f := range floats[i] {
+			input.WriteString("\t" + strconv.FormatFloat(float64(f), 'g', -1, 32))
+		}
+		input.WriteString("\t" + strings.Join(cats[i], "\t") + "\n")
+	}
+
+	expected, err := model.Predict(floats, cats)
+	require.NoError(t, err)
+
+	var output bytes.Buffer
+	n, err := dataset.Score(context.Background(), model, strings.NewReader(input.String()), &output, columns, dataset.Options{BatchSize: 2})
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Equal(t, "SampleId\tRawFormulaVal", lines[0])
+	require.Len(t, lines, 4)
+
+	for i, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		require.Equal(t, strconv.Itoa(i), fields[0])
+
+		pred, err := strconv.ParseFloat(fields[1], 64)
+		require.NoError(t, err)
+		require.Equal(t, expected[i], pred)
+	}
+
+	// Header is written for empty input
+	output.Reset()
+	n, err = dataset.Score(context.Background(), model, strings.NewReader(""), &output, columns, dataset.Options{})
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.Equal(t, "SampleId\tRawFormulaVal\n", output.String())
+
+	// Categ column is described as Num
+	_, err = dataset.Score(context.Background(), model, strings.NewReader(input.String()), io.Discard,
+		columns[:2], dataset.Options{})
+	require.ErrorIs(t, err, cb.ErrSchemaMismatch)
+
+	_, err = dataset.Score(context.Background(), model, strings.NewReader("1\t2\t3\n"), io.Discard,
+		[]dataset.Column{{Index: 0, Type: dataset.Label}}, dataset.Options{})
+	require.ErrorIs(t, err, cb.ErrSchemaMismatch)
+}
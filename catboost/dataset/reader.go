@@ -0,0 +1,220 @@
+package dataset
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+)
+
+// missingValues are values of numeric features treated by CatBoost as missing (NaN).
+var missingValues = map[string]bool{
+	"": true, "#N/A": true, "#N/A N/A": true, "#NA": true, "-1.#IND": true, "-1.#QNAN": true,
+	"-NaN": true, "-nan": true, "1.#IND": true, "1.#QNAN": true, "N/A": true, "NA": true,
+	"NULL": true, "NaN": true, "n/a": true, "nan": true, "null": true, "None": true, "none": true, "-": true,
+}
+
+// Options are options of reading and scoring data file.
+type Options struct {
+	// Delimiter of columns, tab by default as in CatBoost.
+	Delimiter rune
+	// HasHeader is true if first row of data file has names of columns.
+	HasHeader bool
+	// BatchSize is number of rows read and predicted at once, 1000 by default.
+	BatchSize int
+	// PredictionType is type of written predictions, RawFormulaVal by default.
+	PredictionType cb.PredictionType
+}
+
+func (o Options) withDefaults() Options {
+	if o.Delimiter == 0 {
+		o.Delimiter = '\t'
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 1000
+	}
+	if o.PredictionType == "" {
+		o.PredictionType = cb.RawFormulaVal
+	}
+	return o
+}
+
+// Batch is rows of data file split into features of model.
+type Batch struct {
+	Floats [][]float32
+	Cats   [][]string
+	Texts  [][]string
+	// SampleIDs are values of SampleId column or zero-based indices of rows if there is no such column.
+	SampleIDs []string
+}
+
+// Len returns number of rows in batch.
+func (b *Batch) Len() int {
+	return len(b.SampleIDs)
+}
+
+// Reader reads data file in batches.
+type Reader struct {
+	csv     *csv.Reader
+	opts    Options
+	columns map[int]Column
+
+	// features are feature columns in order of model features, set by first row.
+	features []Column
+	// types are types of columns, "" for columns which aren't features.
+	types    []ColumnType
+	sampleID int
+	header   bool
+	row      int
+}
+
+// NewReader returns reader of data file with columns of column description.
+// Values follow CatBoost conventions: columns missing in column description are Num,
+// missing values of Num columns (empty, NaN, NA, null, etc.) are NaN, quotes follow CSV rules.
+func NewReader(r io.Reader, columns []Column, opts Options) *Reader {
+	opts = opts.withDefaults()
+
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Delimiter
+	reader.LazyQuotes = true
+	reader.ReuseRecord = true
+
+	described := make(map[int]Column, len(columns))
+	for _, column := range columns {
+		described[column.Index] = column
+	}
+
+	return &Reader{csv: reader, opts: opts, columns: described, sampleID: -1, header: opts.HasHeader}
+}
+
+// Features returns feature columns in order of model features, it is nil before first Read.
+func (r *Reader) Features() []Column {
+	return r.features
+}
+
+// Read returns next batch of rows, io.EOF if there are no more rows.
+func (r *Reader) Read() (*Batch, error) {
+	batch := &Batch{}
+
+	for batch.Len() < r.opts.BatchSize {
+		record, err := r.csv.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if r.features == nil {
+			if err := r.init(record); err != nil {
+				return nil, err
+			}
+		}
+
+		if r.header {
+			r.header = false
+			continue
+		}
+
+		if err := r.append(batch, record); err != nil {
+			return nil, err
+		}
+		r.row++
+	}
+
+	if batch.Len() == 0 {
+		return nil, io.EOF
+	}
+
+	return batch, nil
+}
+
+// init sets layout of columns by first row, names of columns are taken from header.
+func (r *Reader) init(record []string) error {
+	for index := range r.columns {
+		if index >= len(record) {
+			return fmt.Errorf("%w: column %d is described, data has %d columns", ErrInvalidColumnDescription, index, len(record))
+		}
+	}
+
+	r.types = make([]ColumnType, len(record))
+	r.features = []Column{}
+
+	for index := range record {
+		column, ok := r.columns[index]
+		if !ok {
+			column = Column{Index: index, Type: Num}
+		}
+
+		if column.Name == "" && r.header {
+			column.Name = record[index]
+		}
+
+		if column.Type == SampleID {
+			r.sampleID = index
+		}
+
+		switch column.Kind() {
+		case "":
+			continue
+		case cb.EmbeddingFeature:
+			return fmt.Errorf("%w: %s column %d is not supported", ErrInvalidColumnDescription, column.Type, index)
+		}
+
+		r.types[index] = column.Type
+		r.features = append(r.features, column)
+	}
+
+	return nil
+}
+
+// append adds values of record to batch.
+func (r *Reader) append(batch *Batch, record []string) error {
+	var floats []float32
+	var cats, texts []string
+
+	for index, value := range record {
+		switch r.types[index] {
+		case Num:
+			f, err := parseNum(value)
+			if err != nil {
+				return fmt.Errorf("row %d, column %d: %w", r.row, index, err)
+			}
+			floats = append(floats, f)
+		case Categ:
+			cats = append(cats, value)
+		case Text:
+			texts = append(texts, value)
+		}
+	}
+
+	sampleID := strconv.Itoa(r.row)
+	if r.sampleID >= 0 {
+		sampleID = record[r.sampleID]
+	}
+
+	batch.Floats = append(batch.Floats, floats)
+	batch.Cats = append(batch.Cats, cats)
+	batch.Texts = append(batch.Texts, texts)
+	batch.SampleIDs = append(batch.SampleIDs, sampleID)
+
+	return nil
+}
+
+// parseNum returns value of numeric feature, NaN for missing value.
+func parseNum(value string) (float32, error) {
+	if missingValues[value] {
+		return float32(math.NaN()), nil
+	}
+
+	f, err := strconv.ParseFloat(value, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value of Num column %q", value)
+	}
+
+	return float32(f), nil
+}
@@ -0,0 +1,170 @@
+package dataset
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+)
+
+// Score reads rows of data file r in batches, predicts them by model and writes predictions to w
+// with the same delimiter: header `SampleId<TAB>RawFormulaVal` (or `RawFormulaVal:Class=<label>` column
+// per dimension) and row per sample. Feature columns must match features of model, otherwise
+// catboost.ErrSchemaMismatch is returned. The header is written even if there are no rows.
+// It returns number of scored rows.
+//
+// Predictions are made by model itself if its prediction type is the same as opts.PredictionType,
+// otherwise by a copy of model loaded once by catboost.Model.PredictWith.
+func Score(
+	ctx context.Context, model *cb.Model, r io.Reader, w io.Writer, columns []Column, opts Options,
+) (int, error) {
+	opts = opts.withDefaults()
+
+	schema, err := model.Schema()
+	if err != nil {
+		return 0, err
+	}
+
+	reader := NewReader(r, columns, opts)
+
+	writer := csv.NewWriter(w)
+	writer.Comma = opts.Delimiter
+
+	predictOpts := cb.PredictOptions{Type: opts.PredictionType}
+	withTexts := len(schema.Names(cb.TextFeature)) > 0
+
+	dimensions := schema.Dimensions
+	if opts.PredictionType == cb.Class {
+		dimensions = 1
+	}
+
+	if err := writer.Write(predictionHeader(opts.PredictionType, dimensions, schema.ClassLabels)); err != nil {
+		return 0, err
+	}
+
+	var scored int
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return scored, err
+		}
+
+		batch, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return scored, err
+		}
+
+		if scored == 0 {
+			if err := checkFeatures(reader.Features(), schema); err != nil {
+				return scored, err
+			}
+		}
+
+		// Extra trailing features of data are not passed to model
+		batch.Floats = trimRows(batch.Floats, len(schema.Names(cb.FloatFeature)))
+		batch.Cats = trimRows(batch.Cats, len(schema.Names(cb.CatFeature)))
+		batch.Texts = trimRows(batch.Texts, len(schema.Names(cb.TextFeature)))
+
+		var preds []float64
+		if withTexts {
+			preds, err = model.PredictTextWith(ctx, predictOpts, batch.Floats, batch.Cats, batch.Texts)
+		} else {
+			preds, err = model.PredictWith(ctx, predictOpts, batch.Floats, batch.Cats)
+		}
+		if err != nil {
+			return scored, err
+		}
+
+		if len(preds) != batch.Len()*dimensions {
+			return scored, fmt.Errorf("%w: got %d predictions for %d rows, expected %d per row",
+				cb.ErrSchemaMismatch, len(preds), batch.Len(), dimensions)
+		}
+
+		if err := writePredictions(writer, batch.SampleIDs, preds, dimensions); err != nil {
+			return scored, err
+		}
+
+		scored += batch.Len()
+	}
+
+	writer.Flush()
+
+	return scored, writer.Error()
+}
+
+// writePredictions writes row of predictions per sample and flushes writer.
+func writePredictions(writer *csv.Writer, sampleIDs []string, preds []float64, dimensions int) error {
+	record := make([]string, dimensions+1)
+	for i, sampleID := range sampleIDs {
+		record[0] = sampleID
+		for j, pred := range preds[i*dimensions : (i+1)*dimensions] {
+			record[j+1] = strconv.FormatFloat(pred, 'g', -1, 64)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// checkFeatures returns catboost.ErrSchemaMismatch if feature columns don't match features of model.
+// Data may have extra trailing features, Score drops them before prediction.
+func checkFeatures(features []Column, schema *cb.Schema) error {
+	if len(schema.Names(cb.EmbeddingFeature)) > 0 {
+		return fmt.Errorf("%w: embedding features are not supported", cb.ErrSchemaMismatch)
+	}
+
+	if len(features) < len(schema.Features) {
+		return fmt.Errorf("%w: data has %d features, model has %d", cb.ErrSchemaMismatch, len(features), len(schema.Features))
+	}
+
+	for i, feature := range schema.Features {
+		if kind := features[i].Kind(); kind != feature.Kind {
+			return fmt.Errorf("%w: feature %d %q is %s in model, column %d is %s",
+				cb.ErrSchemaMismatch, i, feature.Name, feature.Kind, features[i].Index, features[i].Type)
+		}
+	}
+
+	return nil
+}
+
+// trimRows cuts every row to first n values.
+func trimRows[T any](rows [][]T, n int) [][]T {
+	for i, row := range rows {
+		if len(row) > n {
+			rows[i] = row[:n]
+		}
+	}
+
+	return rows
+}
+
+// predictionHeader returns names of columns of predictions as in CatBoost output.
+func predictionHeader(predictionType cb.PredictionType, dimensions int, classLabels []string) []string {
+	header := []string{string(SampleID)}
+
+	if dimensions == 1 {
+		return append(header, string(predictionType))
+	}
+
+	for i := 0; i < dimensions; i++ {
+		label := strconv.Itoa(i)
+		if len(classLabels) == dimensions {
+			label = classLabels[i]
+		}
+		header = append(header, fmt.Sprintf("%s:Class=%s", predictionType, label))
+	}
+
+	return header
+}
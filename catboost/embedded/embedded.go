@@ -0,0 +1,105 @@
+// Package embedded loads CatBoost shared library embedded into Go binary,
+// so service is shipped as a single artifact without libcatboostmodel and CATBOOST_LIBRARY_PATH.
+//
+//	//go:embed libcatboostmodel.so
+//	var library []byte
+//
+//	func main() {
+//		if err := embedded.SetDefault(library); err != nil {
+//			log.Fatal(err)
+//		}
+//		model, err := catboost.LoadFullModelFromFile("model.cbm")
+//		...
+//	}
+package embedded
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+)
+
+// ErrEmptyLibrary is returned for empty copy of library.
+var ErrEmptyLibrary = errors.New("empty catboost library")
+
+// Open loads CatBoost shared library from memory copy.
+// On Linux the copy is written to anonymous memory file (memfd_create),
+// otherwise or if it fails to temporary file removed after loading.
+// The library is opened by catboost.OpenLibraryData and is never unloaded.
+func Open(data []byte) (*cb.Library, error) {
+	if len(data) == 0 {
+		return nil, ErrEmptyLibrary
+	}
+
+	if f, path, ok := memoryFile(data); ok {
+		// Memory file can't be opened in some environments (e.g. without /proc), temporary file is tried then
+		lib, err := cb.OpenLibraryData(path, data)
+		if err != nil {
+			f.Close()
+			return openTempFile(data)
+		}
+
+		// The memory file is kept open for the process lifetime, so the path stays valid
+		memoryFilesMu.Lock()
+		memoryFiles = append(memoryFiles, f)
+		memoryFilesMu.Unlock()
+
+		return lib, nil
+	}
+
+	return openTempFile(data)
+}
+
+var (
+	memoryFilesMu sync.Mutex
+	// memoryFiles keep memory files of opened libraries open and reachable.
+	memoryFiles []*os.File
+)
+
+// OpenFS loads CatBoost shared library from file name of fsys (e.g. embed.FS).
+func OpenFS(fsys fs.FS, name string) (*cb.Library, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cb.ErrLoadLibrary, err)
+	}
+
+	return Open(data)
+}
+
+// SetDefault loads CatBoost shared library from memory copy and makes it default library
+// of package-level functions (e.g. catboost.LoadFullModelFromFile).
+func SetDefault(data []byte) error {
+	lib, err := Open(data)
+	if err != nil {
+		return err
+	}
+
+	cb.SetDefaultLibrary(lib)
+
+	return nil
+}
+
+// openTempFile writes library to temporary file and opens it.
+// The file is removed after loading, the library stays mapped into memory.
+func openTempFile(data []byte) (*cb.Library, error) {
+	f, err := os.CreateTemp("", "libcatboostmodel-*"+libraryExt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", cb.ErrLoadLibrary, err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%w: %w", cb.ErrLoadLibrary, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("%w: %w", cb.ErrLoadLibrary, err)
+	}
+
+	return cb.OpenLibraryData(f.Name(), data)
+}
@@ -0,0 +1,37 @@
+package embedded_test
+
+import (
+	"os"
+	"testing"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+	"github.com/mirecl/catboost-cgo/catboost/embedded"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	defaultLib, err := cb.DefaultLibrary()
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(defaultLib.Path())
+	require.NoError(t, err)
+
+	lib, err := embedded.Open(data)
+	require.NoError(t, err)
+	require.NotEqual(t, defaultLib.Path(), lib.Path())
+
+	// Version is detected from data, the copy of library may be removed
+	require.NotEmpty(t, lib.Info().Version)
+	require.Equal(t, defaultLib.Info().Version, lib.Info().Version)
+
+	model, err := lib.LoadFullModelFromFile("../../example/regressor/regressor.cbm")
+	require.NoError(t, err)
+	defer model.Close()
+
+	preds, err := model.Predict([][]float32{{2, 4, 6, 8}}, [][]string{{}})
+	require.NoError(t, err)
+	require.Equal(t, []float64{15.625}, preds)
+
+	_, err = embedded.Open(nil)
+	require.ErrorIs(t, err, embedded.ErrEmptyLibrary)
+}
@@ -0,0 +1,48 @@
+package embedded
+
+/*
+#define _GNU_SOURCE
+#include <stdlib.h>
+#include <unistd.h>
+#include <sys/syscall.h>
+
+// MFD_CLOEXEC of linux/memfd.h
+#define EMBEDDED_MFD_CLOEXEC 0x0001U
+
+static int embeddedMemfdCreate(const char *name)
+{
+#ifdef SYS_memfd_create
+	return syscall(SYS_memfd_create, name, EMBEDDED_MFD_CLOEXEC);
+#else
+	return -1;
+#endif
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+const libraryExt = ".so"
+
+// memoryFile writes data to anonymous memory file and returns it with its path.
+func memoryFile(data []byte) (*os.File, string, bool) {
+	name := C.CString("libcatboostmodel")
+	defer C.free(unsafe.Pointer(name))
+
+	fd := C.embeddedMemfdCreate(name)
+	if fd < 0 {
+		return nil, "", false
+	}
+
+	f := os.NewFile(uintptr(fd), "libcatboostmodel")
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return nil, "", false
+	}
+
+	return f, fmt.Sprintf("/proc/self/fd/%d", fd), true
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package embedded
+
+import "os"
+
+const libraryExt = ".dylib"
+
+// memoryFile is supported only on Linux, temporary file is used otherwise.
+func memoryFile([]byte) (*os.File, string, bool) {
+	return nil, "", false
+}
@@ -0,0 +1,115 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Shape is a shape of input data passed to CatBoost shared library.
+type Shape struct {
+	Samples           int
+	FloatFeatures     int
+	CatFeatures       int
+	TextFeatures      int
+	EmbeddingFeatures int
+}
+
+// String returns shape in readable format.
+func (s Shape) String() string {
+	return fmt.Sprintf("samples=%d floats=%d cats=%d texts=%d embeddings=%d",
+		s.Samples, s.FloatFeatures, s.CatFeatures, s.TextFeatures, s.EmbeddingFeatures)
+}
+
+// Error is an error of CatBoost shared library call.
+// Use errors.Is with sentinel errors (e.g. ErrCalcModelPrediction) and errors.As for details.
+type Error struct {
+	// Op is name of failed function of C API (e.g. CalcModelPrediction).
+	Op string
+	// Sentinel is one of package errors describing the cause.
+	Sentinel error
+	// NativeMessage is message stored by CatBoost shared library.
+	NativeMessage string
+	// File and Line are source location of CatBoost parsed from native message.
+	File string
+	Line int
+	// Shape is input shape of call, nil if call has no input data.
+	Shape *Shape
+}
+
+// Error returns message of error.
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	b.WriteString(e.Sentinel.Error())
+	b.WriteString(" (")
+	b.WriteString(e.Op)
+	if e.Shape != nil {
+		b.WriteString(", ")
+		b.WriteString(e.Shape.String())
+	}
+	b.WriteString(")")
+
+	if message := trimNativeMessage(e.NativeMessage); message != "" {
+		b.WriteString(": ")
+		b.WriteString(message)
+	}
+
+	return b.String()
+}
+
+// Unwrap returns sentinel error.
+func (e *Error) Unwrap() error {
+	return e.Sentinel
+}
+
+// newError returns error of C API function op with last message of CatBoost shared library.
+// The message is stored per thread, so the goroutine must be locked to its thread since the failed call.
+func (l *Library) newError(op string, sentinel error, shape *Shape) *Error {
+	e := &Error{
+		Op:            op,
+		Sentinel:      sentinel,
+		NativeMessage: C.GoString(C.WrapGetErrorString(l.fns)),
+		Shape:         shape,
+	}
+	e.File, e.Line = parseNativeLocation(e.NativeMessage)
+
+	return e
+}
+
+// nativeLocation matches source location of CatBoost, e.g. `catboost/libs/model/model.cpp:123: `.
+var nativeLocation = regexp.MustCompile(`^([\w./+-]+):(\d+):`)
+
+// parseNativeLocation returns source file and line from native message.
+func parseNativeLocation(message string) (string, int) {
+	message = strings.TrimPrefix(trimNativeMessage(message), "catboost.git/")
+
+	match := nativeLocation.FindStringSubmatch(message)
+	if match == nil {
+		return "", 0
+	}
+
+	line, err := strconv.Atoi(match[2])
+	if err != nil {
+		return "", 0
+	}
+
+	return match[1], line
+}
+
+// trimNativeMessage returns native message without build path before `catboost.git`.
+func trimNativeMessage(message string) string {
+	message = strings.TrimSpace(message)
+
+	if i := strings.Index(message, "catboost.git"); i != -1 {
+		return message[i:]
+	}
+
+	return message
+}
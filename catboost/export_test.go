@@ -0,0 +1,6 @@
+package catboost
+
+// OpenModels returns number of model handles not released yet.
+func OpenModels() int64 {
+	return openModels.Load()
+}
@@ -0,0 +1,140 @@
+package catboost
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Fingerprint identifies loaded model artifact.
+type Fingerprint struct {
+	// SHA256 is hex-encoded SHA-256 of model as loaded by CatBoost, i.e. of uncompressed `.cbm` content,
+	// so it is the same for file, buffer and compressed artifact of one model.
+	SHA256 string
+	// GUID is model metadata MetaModelGUID.
+	GUID string
+}
+
+// String returns fingerprint as `sha256/guid`.
+func (f Fingerprint) String() string {
+	return f.SHA256 + "/" + f.GUID
+}
+
+// LoadOptions are expectations checked after loading model, empty fields aren't checked.
+type LoadOptions struct {
+	// ExpectedSHA256 is hex-encoded SHA-256 of uncompressed model as Fingerprint.SHA256,
+	// ErrChecksumMismatch if it differs.
+	ExpectedSHA256 string
+	// ExpectedGUID is model metadata MetaModelGUID, ErrGUIDMismatch if it differs.
+	ExpectedGUID string
+	// ExpectedFeatureNames are names of used features in order, ErrSchemaMismatch if they differ.
+	ExpectedFeatureNames []string
+}
+
+// LoadFullModelFromFileWith returns model loaded from file if it matches options.
+func LoadFullModelFromFileWith(filename string, opts LoadOptions) (*Model, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.LoadFullModelFromFileWith(filename, opts)
+}
+
+// LoadFullModelFromBufferWith returns model loaded from memory buffer if it matches options.
+func LoadFullModelFromBufferWith(buffer []byte, opts LoadOptions) (*Model, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.LoadFullModelFromBufferWith(buffer, opts)
+}
+
+// LoadFullModelFromFileWith returns model loaded from file by library if it matches options.
+func (l *Library) LoadFullModelFromFileWith(filename string, opts LoadOptions) (*Model, error) {
+	model, err := l.LoadFullModelFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return verified(model, opts)
+}
+
+// LoadFullModelFromBufferWith returns model loaded from memory buffer by library if it matches options.
+func (l *Library) LoadFullModelFromBufferWith(buffer []byte, opts LoadOptions) (*Model, error) {
+	model, err := l.LoadFullModelFromBuffer(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return verified(model, opts)
+}
+
+// verified returns model if it matches options, the model is closed otherwise.
+func verified(model *Model, opts LoadOptions) (*Model, error) {
+	if err := model.Verify(opts); err != nil {
+		_ = model.Close()
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// Fingerprint returns SHA-256 of uncompressed model with model GUID.
+// The checksum is computed while loading, so it doesn't change if the file is modified after that.
+func (m *Model) Fingerprint() Fingerprint {
+	return Fingerprint{
+		SHA256: hex.EncodeToString(m.checksum[:]),
+		GUID:   m.GetModelInfoValue(MetaModelGUID),
+	}
+}
+
+// Verify returns error if model doesn't match options.
+func (m *Model) Verify(opts LoadOptions) error {
+	fingerprint := m.Fingerprint()
+
+	if opts.ExpectedSHA256 != "" && !strings.EqualFold(opts.ExpectedSHA256, fingerprint.SHA256) {
+		return fmt.Errorf("%w: sha256 %s, expected %s", ErrChecksumMismatch, fingerprint.SHA256, opts.ExpectedSHA256)
+	}
+
+	if opts.ExpectedGUID != "" && opts.ExpectedGUID != fingerprint.GUID {
+		return fmt.Errorf("%w: guid %q, expected %q", ErrGUIDMismatch, fingerprint.GUID, opts.ExpectedGUID)
+	}
+
+	if opts.ExpectedFeatureNames != nil {
+		names, err := m.GetModelUsedFeaturesNames()
+		if err != nil {
+			return err
+		}
+
+		if !slices.Equal(names, opts.ExpectedFeatureNames) {
+			return fmt.Errorf("%w: features %v, expected %v", ErrSchemaMismatch, names, opts.ExpectedFeatureNames)
+		}
+	}
+
+	return nil
+}
+
+// fileChecksum returns SHA-256 of file.
+func fileChecksum(filename string) ([sha256.Size]byte, error) {
+	var sum [sha256.Size]byte
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return sum, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return sum, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+	h.Sum(sum[:0])
+
+	return sum, nil
+}
@@ -0,0 +1,201 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+// PredictFlat returns predictions for samples stored in one contiguous row-major buffer.
+// Row i is data[i*nCols : (i+1)*nCols]. Only models with float features are supported.
+func (m *Model) PredictFlat(data []float32, nRows, nCols int) ([]float64, error) {
+	return m.PredictFlatStride(data, nRows, nCols, nCols)
+}
+
+// PredictFlatStride returns predictions for samples stored in one contiguous row-major buffer
+// with stride between rows (e.g. gonum mat.Dense). Row i is data[i*stride : i*stride+nCols].
+func (m *Model) PredictFlatStride(data []float32, nRows, nCols, stride int) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("CalcModelPredictionFlat"); err != nil {
+		return nil, err
+	}
+
+	if err := checkFlatShape(len(data), nRows, nCols, stride); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkInput(checkColumns(nCols, m.flatFeaturesCount())); err != nil {
+		return nil, err
+	}
+
+	if nRows == 0 {
+		return []float64{}, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	preds := make([]float64, nRows*size)
+
+	floatsC := C.makeFloatArray2DFromFlat((*C.float)(getFloatPointer(data)), C.int(nRows), C.int(stride))
+	defer C.free(unsafe.Pointer(floatsC))
+
+	if !C.WrapCalcModelPredictionFlat(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nRows),
+		floatsC,
+		C.size_t(nCols),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("CalcModelPredictionFlat", ErrCalcModelPredictionFlat, &Shape{
+			Samples: nRows, FloatFeatures: nCols,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictFlatTransposed returns predictions for samples stored in one contiguous column-major buffer.
+// Feature j of all samples is data[j*nRows : (j+1)*nRows]. Only models with float features are supported.
+func (m *Model) PredictFlatTransposed(data []float32, nRows, nCols int) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("CalcModelPredictionFlatTransposed"); err != nil {
+		return nil, err
+	}
+
+	if err := checkFlatShape(len(data), nCols, nRows, nRows); err != nil {
+		return nil, err
+	}
+
+	if err := m.checkInput(checkColumns(nCols, m.flatFeaturesCount())); err != nil {
+		return nil, err
+	}
+
+	if nRows == 0 {
+		return []float64{}, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	preds := make([]float64, nRows*size)
+
+	floatsC := C.makeFloatArray2DFromFlat((*C.float)(getFloatPointer(data)), C.int(nCols), C.int(nRows))
+	defer C.free(unsafe.Pointer(floatsC))
+
+	if !C.WrapCalcModelPredictionFlatTransposed(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nRows),
+		floatsC,
+		C.size_t(nCols),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("CalcModelPredictionFlatTransposed", ErrCalcModelPredictionFlat, &Shape{
+			Samples: nRows, FloatFeatures: nCols,
+		})
+	}
+
+	return preds, nil
+}
+
+// PredictFlatTransposedColumns returns predictions for samples stored by columns in separate buffers,
+// e.g. columns of Arrow record batch. Column j is feature j of all nRows samples.
+// Categorical features are passed as hashes (see CatHasher) converted by CatHashToFloat.
+func (m *Model) PredictFlatTransposedColumns(columns [][]float32, nRows int) ([]float64, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := m.lib.checkSymbols("CalcModelPredictionFlatTransposed"); err != nil {
+		return nil, err
+	}
+
+	for j, column := range columns {
+		if len(column) < nRows {
+			return nil, fmt.Errorf("%w: column %d has %d values, expected %d", ErrFlatShape, j, len(column), nRows)
+		}
+	}
+
+	if err := m.checkInput(checkColumns(len(columns), m.flatFeaturesCount())); err != nil {
+		return nil, err
+	}
+
+	if nRows == 0 {
+		return []float64{}, nil
+	}
+
+	// Special for Multiclassification (size > 1)
+	size := m.rowResultSize()
+
+	preds := make([]float64, nRows*size)
+
+	floatsC := makeFloatArray2D(columns)
+	defer C.free(unsafe.Pointer(floatsC))
+
+	if !C.WrapCalcModelPredictionFlatTransposed(
+		m.lib.fns,
+		m.handler,
+		C.size_t(nRows),
+		floatsC,
+		C.size_t(len(columns)),
+		(*C.double)(&preds[0]),
+		C.size_t(len(preds)),
+	) {
+		return nil, m.lib.newError("CalcModelPredictionFlatTransposed", ErrCalcModelPredictionFlat, &Shape{
+			Samples: nRows, FloatFeatures: len(columns),
+		})
+	}
+
+	return preds, nil
+}
+
+// CatHashToFloat returns hash of categorical value as float feature of flat layout,
+// CatBoost reinterprets bits of the hash as float.
+func CatHashToFloat(hash int32) float32 {
+	return math.Float32frombits(uint32(hash))
+}
+
+// checkFlatShape returns error if buffer of size can't hold n vectors of length with stride.
+func checkFlatShape(size, n, length, stride int) error {
+	if n < 0 || length < 0 || stride < length {
+		return fmt.Errorf("%w: n=%d length=%d stride=%d", ErrFlatShape, n, length, stride)
+	}
+
+	if n > 0 && size < (n-1)*stride+length {
+		return fmt.Errorf("%w: buffer size %d is less than required %d", ErrFlatShape, size, (n-1)*stride+length)
+	}
+
+	return nil
+}
+
+// flatFeaturesCount returns number of columns in flat layout: float and categorical features.
+func (m *Model) flatFeaturesCount() int {
+	return m.floatFeaturesCount() + m.catFeaturesCount()
+}
+
+// getFloatPointer returns pointer to the first element or nil for empty slice.
+func getFloatPointer(data []float32) unsafe.Pointer {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return unsafe.Pointer(&data[0])
+}
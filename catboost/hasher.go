@@ -0,0 +1,104 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"sync"
+	"unsafe"
+)
+
+// CatHasher calculates hashes of categorical values for Model.PredictHashed.
+// Hashes are calculated by CatBoost shared library, so values are compatible with Model.Predict.
+// Safe for concurrent use.
+type CatHasher struct {
+	lib       *Library
+	mu        sync.RWMutex
+	cache     map[string]int32
+	cacheSize int
+}
+
+// NewCatHasher returns hasher of categorical values.
+// If cacheSize > 0, the hasher memoizes up to cacheSize string values,
+// when the cache is full it is reset.
+func NewCatHasher(cacheSize int) (*CatHasher, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.NewCatHasher(cacheSize)
+}
+
+// NewCatHasher returns hasher of categorical values calculated by library, see NewCatHasher.
+func (l *Library) NewCatHasher(cacheSize int) (*CatHasher, error) {
+	if err := l.checkSymbols("GetStringCatFeatureHash", "GetIntegerCatFeatureHash"); err != nil {
+		return nil, err
+	}
+
+	h := &CatHasher{lib: l, cacheSize: cacheSize}
+	if cacheSize > 0 {
+		h.cache = make(map[string]int32, cacheSize)
+	}
+
+	return h, nil
+}
+
+// String returns hash of categorical string value.
+func (h *CatHasher) String(value string) int32 {
+	if h.cacheSize <= 0 {
+		return h.hashString(value)
+	}
+
+	h.mu.RLock()
+	hash, ok := h.cache[value]
+	h.mu.RUnlock()
+
+	if ok {
+		return hash
+	}
+
+	hash = h.hashString(value)
+
+	h.mu.Lock()
+	if len(h.cache) >= h.cacheSize {
+		clear(h.cache)
+	}
+	h.cache[value] = hash
+	h.mu.Unlock()
+
+	return hash
+}
+
+// Integer returns hash of categorical integer value.
+// The hash is equal to hash of the decimal string representation of the value.
+func (h *CatHasher) Integer(value int64) int32 {
+	return int32(C.WrapGetIntegerCatFeatureHash(h.lib.fns, C.longlong(value)))
+}
+
+// Row returns hashes of categorical values for a single sample.
+func (h *CatHasher) Row(cats []string) []int32 {
+	hashes := make([]int32, 0, len(cats))
+	for _, c := range cats {
+		hashes = append(hashes, h.String(c))
+	}
+
+	return hashes
+}
+
+// Rows returns hashes of categorical values for samples.
+func (h *CatHasher) Rows(cats [][]string) [][]int32 {
+	hashes := make([][]int32, 0, len(cats))
+	for _, row := range cats {
+		hashes = append(hashes, h.Row(row))
+	}
+
+	return hashes
+}
+
+func (h *CatHasher) hashString(value string) int32 {
+	data := unsafe.StringData(value)
+	return int32(C.WrapGetStringCatFeatureHash(h.lib.fns, (*C.char)(unsafe.Pointer(data)), C.size_t(len(value))))
+}
@@ -0,0 +1,215 @@
+package catboost
+
+/*
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+)
+
+// Capabilities are optional features supported by loaded CatBoost shared library.
+type Capabilities struct {
+	// Text is inference with text features.
+	Text bool
+	// Embeddings is inference with embedding features.
+	Embeddings bool
+	// Staged is staged inference (PredictStaged).
+	Staged bool
+	// GPU is evaluation on CUDA GPU device.
+	GPU bool
+}
+
+// LibraryReport describes CatBoost shared library loaded at runtime.
+type LibraryReport struct {
+	// Path is resolved path of library.
+	Path string
+	// Version is runtime version of library (e.g. v1.2.8), empty if it isn't detected.
+	Version string
+	// Symbols are sorted names of resolved functions of C API.
+	Symbols []string
+	// Capabilities are derived from resolved functions.
+	Capabilities Capabilities
+}
+
+// LibraryInfo loads default CatBoost shared library if needed and returns report about it.
+// Unlike Version it describes the library actually loaded, not the header used for build.
+func LibraryInfo() (*LibraryReport, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.Info(), nil
+}
+
+// Info returns report about library.
+func (l *Library) Info() *LibraryReport {
+	symbols := make([]string, 0, len(l.symbols))
+	for fnName := range l.symbols {
+		symbols = append(symbols, fnName)
+	}
+	sort.Strings(symbols)
+
+	return &LibraryReport{
+		Path:    l.path,
+		Version: l.version(),
+		Symbols: symbols,
+		Capabilities: Capabilities{
+			Text: l.checkSymbols(
+				"CalcModelPredictionText", "GetTextFeaturesCount", "GetTextFeatureIndices",
+			) == nil,
+			Embeddings: l.checkSymbols(
+				"CalcModelPredictionTextAndEmbeddings", "GetEmbeddingFeaturesCount",
+			) == nil,
+			Staged: l.checkSymbols("CalcModelPredictionStaged", "GetTreeCount") == nil,
+			GPU:    l.supportedGPU(),
+		},
+	}
+}
+
+// OpenLibraryData is like OpenLibrary, but version of library is detected from data, the content of path.
+// It is used for copies of library (e.g. embedded into binary) which are removed after opening.
+func OpenLibraryData(path string, data []byte) (*Library, error) {
+	lib, err := OpenLibrary(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lib.versionOnce.Do(func() {
+		lib.versionValue = scanVersion(bytes.NewReader(data))
+	})
+
+	return lib, nil
+}
+
+// AtLeast returns true if version of library is detected and not older than version.
+// Version may be semantic version (e.g. v1.2 or 1.2.8) or model metadata MetaVersionInfo.
+func (r *LibraryReport) AtLeast(version string) bool {
+	current, ok := parseVersion(r.Version)
+	if !ok {
+		return false
+	}
+
+	required, ok := parseVersion(version)
+	if !ok {
+		return false
+	}
+
+	return slices.Compare(current, required) >= 0
+}
+
+// libraryVersion matches version in build info of CatBoost, e.g. `Branch: tags/v1.2.8`.
+var libraryVersion = regexp.MustCompile(`tags/v(\d+\.\d+(?:\.\d+)?)`)
+
+// semanticVersion matches version, e.g. `v1.2.8` or `1.2`.
+var semanticVersion = regexp.MustCompile(`^v?(\d+)\.(\d+)(?:\.(\d+))?$`)
+
+// versionScanChunk is size of library chunk scanned for build info at once.
+const versionScanChunk = 1 << 20
+
+// versionScanOverlap is size of chunk tail scanned again with next chunk,
+// so build info split between chunks is found.
+const versionScanOverlap = 64
+
+// version returns runtime version from build info embedded into library, it is detected once.
+func (l *Library) version() string {
+	l.versionOnce.Do(func() {
+		f, err := os.Open(l.path)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+
+		l.versionValue = scanVersion(f)
+	})
+
+	return l.versionValue
+}
+
+// scanVersion returns version from build info read from r chunk by chunk, empty if it isn't found.
+func scanVersion(r io.Reader) string {
+	buf := make([]byte, versionScanOverlap+versionScanChunk)
+	keep := 0
+
+	for {
+		n, err := io.ReadFull(r, buf[keep:])
+		data := buf[:keep+n]
+		eof := err != nil
+
+		// Match starting in the tail may continue in next chunk, it is checked again with it
+		if loc := libraryVersion.FindSubmatchIndex(data); loc != nil && (eof || loc[0] < len(data)-versionScanOverlap) {
+			return "v" + string(data[loc[2]:loc[3]])
+		}
+
+		if eof {
+			return ""
+		}
+
+		keep = min(versionScanOverlap, len(data))
+		copy(buf, data[len(data)-keep:])
+	}
+}
+
+// parseVersion returns major, minor and patch of version or build info.
+func parseVersion(version string) ([]int, bool) {
+	if match := libraryVersion.FindStringSubmatch(version); match != nil {
+		version = match[1]
+	}
+
+	match := semanticVersion.FindStringSubmatch(version)
+	if match == nil {
+		return nil, false
+	}
+
+	parts := make([]int, 3)
+	for i, part := range match[1:] {
+		if part == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		parts[i] = n
+	}
+
+	return parts, true
+}
+
+// supportedGPU returns true if library is able to evaluate models on GPU.
+func (l *Library) supportedGPU() bool {
+	if l.checkSymbols("EnableGPUEvaluation", "GetSupportedEvaluatorTypes") != nil {
+		return false
+	}
+
+	// Supported evaluators don't depend on model, so empty model handle is enough
+	m := &Model{lib: l, handler: C.WrapModelCalcerCreate(l.fns)}
+	defer C.WrapModelCalcerDelete(l.fns, m.handler)
+
+	devices, err := m.GetSupportedEvaluatorTypes()
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(devices, GPU)
+}
+
+// String returns report in readable format.
+func (r *LibraryReport) String() string {
+	version := r.Version
+	if version == "" {
+		version = "unknown"
+	}
+
+	return fmt.Sprintf("%s (version %s, %d symbols, %+v)", r.Path, version, len(r.Symbols), r.Capabilities)
+}
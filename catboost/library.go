@@ -0,0 +1,370 @@
+package catboost
+
+/*
+#include <dlfcn.h>
+#include <catboost_wrapper.h>
+*/
+import "C"
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// requiredSymbols are functions of CatBoost shared library without which the package can't work.
+var requiredSymbols = []string{
+	"ModelCalcerCreate",
+	"ModelCalcerDelete",
+	"LoadFullModelFromBuffer",
+	"CalcModelPredictionSingle",
+	"CalcModelPrediction",
+	"GetErrorString",
+	"GetFloatFeaturesCount",
+	"GetCatFeaturesCount",
+	"GetDimensionsCount",
+	"SetPredictionTypeString",
+	"GetModelUsedFeaturesNames",
+	"GetModelInfoValue",
+	"GetCatFeatureIndices",
+	"GetFloatFeatureIndices",
+}
+
+// optionalSymbols are functions missing in older CatBoost shared libraries.
+// API depending on missing function returns ErrNotSupportedByLibrary.
+var optionalSymbols = []string{
+	"LoadFullModelFromFile",
+	"CalcModelPredictionText",
+	"GetTextFeaturesCount",
+	"GetTextFeatureIndices",
+	"GetSupportedEvaluatorTypes",
+	"EnableGPUEvaluation",
+	"GetEmbeddingFeaturesCount",
+	"GetEmbeddingFeatureIndices",
+	"CalcModelPredictionTextAndEmbeddings",
+	"GetStringCatFeatureHash",
+	"GetIntegerCatFeatureHash",
+	"CalcModelPredictionWithHashedCatFeatures",
+	"CalcModelPredictionWithHashedCatFeaturesAndTextFeatures",
+	"CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures",
+	"PredictSpecificClass",
+	"PredictSpecificClassSingle",
+	"PredictSpecificClassText",
+	"PredictSpecificClassTextAndEmbeddings",
+	"PredictSpecificClassWithHashedCatFeatures",
+	"PredictSpecificClassWithHashedCatFeaturesAndTextFeatures",
+	"PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures",
+	"CalcModelPredictionFlat",
+	"CalcModelPredictionFlatTransposed",
+	"GetTreeCount",
+	"CalcModelPredictionStaged",
+	"CalcModelPredictionTextStaged",
+	"CalcModelPredictionSingleStaged",
+}
+
+// Library is CatBoost shared library with its own table of functions.
+// Several libraries (e.g. different versions of libcatboostmodel) can be used side by side in one process,
+// each Model is bound to the library it is loaded from.
+type Library struct {
+	path   string
+	handle unsafe.Pointer
+	fns    *C.CatBoostLibrary
+	// symbols are names of loaded functions.
+	symbols map[string]bool
+
+	versionOnce  sync.Once
+	versionValue string
+}
+
+var (
+	librariesMu sync.Mutex
+	// libraries are opened libraries by absolute path, dlopen returns the same handle for the same file.
+	libraries = map[string]*Library{}
+)
+
+// OpenLibrary loads CatBoost shared library from path.
+// Opening the same path again returns the same library. Libraries are never unloaded.
+func OpenLibrary(path string) (*Library, error) {
+	if !checkPlatform() {
+		return nil, ErrNotSupportedPlatform
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w `%s`: %w", ErrLoadLibrary, path, err)
+	}
+
+	if _, err := os.Stat(absPath); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFoundLibrary, path)
+	}
+
+	librariesMu.Lock()
+	defer librariesMu.Unlock()
+
+	if lib, ok := libraries[absPath]; ok {
+		return lib, nil
+	}
+
+	cName := C.CString(absPath)
+	defer C.free(unsafe.Pointer(cName))
+
+	// RTLD_LOCAL keeps symbols of different libraries apart
+	handle := C.dlopen(cName, C.RTLD_LAZY|C.RTLD_LOCAL)
+	if handle == nil {
+		msg := C.GoString(C.dlerror())
+		return nil, fmt.Errorf("%w `%s`: %s", ErrLoadLibrary, path, msg)
+	}
+
+	lib := &Library{
+		path:    absPath,
+		handle:  handle,
+		fns:     C.NewCatBoostLibrary(),
+		symbols: make(map[string]bool),
+	}
+
+	// Load function from CatBoost shared library
+	for _, fnName := range requiredSymbols {
+		if !lib.registerFn(fnName) {
+			msg := C.GoString(C.dlerror())
+			C.free(unsafe.Pointer(lib.fns))
+			C.dlclose(handle)
+			return nil, fmt.Errorf("%w `%s`: %s", ErrLoadLibrary, path, msg)
+		}
+	}
+
+	for _, fnName := range optionalSymbols {
+		lib.registerFn(fnName)
+	}
+
+	libraries[absPath] = lib
+
+	return lib, nil
+}
+
+// Path returns absolute path of library.
+func (l *Library) Path() string {
+	return l.path
+}
+
+// LoadFullModelFromFile returns model loaded from file by library.
+// The file is read by CatBoost if library supports it, otherwise it is memory-mapped,
+// so the model isn't copied into Go memory. The checksum of the file is computed by streaming hash.
+func (l *Library) LoadFullModelFromFile(filename string) (*Model, error) {
+	filename, err := checkModelFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if l.checkSymbols("LoadFullModelFromFile") != nil {
+		return l.LoadFullModelFromMmap(filename)
+	}
+
+	checksum, err := fileChecksum(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	cFilename := C.CString(filename)
+	defer C.free(unsafe.Pointer(cFilename))
+
+	// Error message is stored per thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	handler := C.WrapModelCalcerCreate(l.fns)
+
+	if !C.WrapLoadFullModelFromFile(l.fns, handler, cFilename) {
+		err := l.newError("LoadFullModelFromFile", ErrLoadFullModelFromFile, nil)
+		C.WrapModelCalcerDelete(l.fns, handler)
+		return nil, err
+	}
+
+	m := newModel(l, handler, nil)
+	m.filename = filename
+	m.checksum = checksum
+
+	return m, nil
+}
+
+// LoadFullModelFromBuffer returns model loaded from memory buffer by library.
+// The buffer must not be modified after loading, it is used for PredictWith.
+func (l *Library) LoadFullModelFromBuffer(buffer []byte) (*Model, error) {
+	handler, err := l.loadBuffer(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	m := newModel(l, handler, buffer)
+	m.checksum = sha256.Sum256(buffer)
+
+	return m, nil
+}
+
+// loadBuffer returns new model handle loaded from buffer, CatBoost copies the buffer.
+func (l *Library) loadBuffer(buffer []byte) (unsafe.Pointer, error) {
+	if len(buffer) == 0 {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromBuffer, ErrEmptyModel)
+	}
+
+	// Error message is stored per thread
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	handler := C.WrapModelCalcerCreate(l.fns)
+
+	if !C.WrapLoadFullModelFromBuffer(l.fns, handler, unsafe.Pointer(&buffer[0]), C.size_t(len(buffer))) {
+		err := l.newError("LoadFullModelFromBuffer", ErrLoadFullModelFromBuffer, nil)
+		C.WrapModelCalcerDelete(l.fns, handler)
+		return nil, err
+	}
+
+	return handler, nil
+}
+
+// checkModelFile returns absolute path of model file or error if file can't be loaded.
+func checkModelFile(filename string) (string, error) {
+	absFilename, err := filepath.Abs(filename)
+	if err != nil {
+		return "", fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	info, err := os.Stat(absFilename)
+	if err != nil {
+		return "", fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	if info.IsDir() {
+		return "", fmt.Errorf("%w: %s is a directory", ErrLoadFullModelFromFile, filename)
+	}
+
+	if info.Size() == 0 {
+		return "", fmt.Errorf("%w: %w: %s", ErrLoadFullModelFromFile, ErrEmptyModel, filename)
+	}
+
+	return absFilename, nil
+}
+
+// checkSymbols returns ErrNotSupportedByLibrary if any function is missing in library.
+func (l *Library) checkSymbols(fnNames ...string) error {
+	for _, fnName := range fnNames {
+		if !l.symbols[fnName] {
+			return fmt.Errorf("%w: %s", ErrNotSupportedByLibrary, fnName)
+		}
+	}
+
+	return nil
+}
+
+// registerFn registers function from library, returns false if function is missing.
+//
+//nolint:funlen
+func (l *Library) registerFn(fnName string) bool {
+	fnC := getFromLibraryFn(l.handle, fnName)
+	if fnC == nil {
+		return false
+	}
+
+	switch fnName {
+	case "ModelCalcerCreate":
+		C.SetModelCalcerCreateFn(l.fns, fnC)
+	case "LoadFullModelFromBuffer":
+		C.SetLoadFullModelFromBufferFn(l.fns, fnC)
+	case "LoadFullModelFromFile":
+		C.SetLoadFullModelFromFileFn(l.fns, fnC)
+	case "CalcModelPredictionSingle":
+		C.SetCalcModelPredictionSingleFn(l.fns, fnC)
+	case "CalcModelPrediction":
+		C.SetCalcModelPredictionFn(l.fns, fnC)
+	case "CalcModelPredictionText":
+		C.SetCalcModelPredictionTextFn(l.fns, fnC)
+	case "GetErrorString":
+		C.SetGetErrorStringFn(l.fns, fnC)
+	case "GetFloatFeaturesCount":
+		C.SetGetFloatFeaturesCountFn(l.fns, fnC)
+	case "GetCatFeaturesCount":
+		C.SetGetCatFeaturesCountFn(l.fns, fnC)
+	case "GetTextFeaturesCount":
+		C.SetGetTextFeaturesCountFn(l.fns, fnC)
+	case "SetPredictionTypeString":
+		C.SetSetPredictionTypeStringFn(l.fns, fnC)
+	case "GetDimensionsCount":
+		C.SetGetDimensionsCountFn(l.fns, fnC)
+	case "GetModelUsedFeaturesNames":
+		C.SetGetModelUsedFeaturesNamesFn(l.fns, fnC)
+	case "GetModelInfoValue":
+		C.SetGetModelInfoValueFn(l.fns, fnC)
+	case "GetCatFeatureIndices":
+		C.SetGetCatFeatureIndicesFn(l.fns, fnC)
+	case "GetFloatFeatureIndices":
+		C.SetGetFloatFeatureIndicesFn(l.fns, fnC)
+	case "GetTextFeatureIndices":
+		C.SetGetTextFeatureIndicesFn(l.fns, fnC)
+	case "GetSupportedEvaluatorTypes":
+		C.SetGetSupportedEvaluatorTypesFn(l.fns, fnC)
+	case "EnableGPUEvaluation":
+		C.SetGetEnableGPUEvaluationFn(l.fns, fnC)
+	case "ModelCalcerDelete":
+		C.SetModelCalcerDeleteFn(l.fns, fnC)
+	case "GetEmbeddingFeaturesCount":
+		C.SetGetEmbeddingFeaturesCountFn(l.fns, fnC)
+	case "GetEmbeddingFeatureIndices":
+		C.SetGetEmbeddingFeatureIndicesFn(l.fns, fnC)
+	case "CalcModelPredictionTextAndEmbeddings":
+		C.SetCalcModelPredictionTextAndEmbeddingsFn(l.fns, fnC)
+	case "GetStringCatFeatureHash":
+		C.SetGetStringCatFeatureHashFn(l.fns, fnC)
+	case "GetIntegerCatFeatureHash":
+		C.SetGetIntegerCatFeatureHashFn(l.fns, fnC)
+	case "CalcModelPredictionWithHashedCatFeatures":
+		C.SetCalcModelPredictionWithHashedCatFeaturesFn(l.fns, fnC)
+	case "CalcModelPredictionWithHashedCatFeaturesAndTextFeatures":
+		C.SetCalcModelPredictionWithHashedCatFeaturesAndTextFeaturesFn(l.fns, fnC)
+	case "CalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeatures":
+		C.SetCalcModelPredictionWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(l.fns, fnC)
+	case "PredictSpecificClass":
+		C.SetPredictSpecificClassFn(l.fns, fnC)
+	case "PredictSpecificClassSingle":
+		C.SetPredictSpecificClassSingleFn(l.fns, fnC)
+	case "PredictSpecificClassText":
+		C.SetPredictSpecificClassTextFn(l.fns, fnC)
+	case "PredictSpecificClassTextAndEmbeddings":
+		C.SetPredictSpecificClassTextAndEmbeddingsFn(l.fns, fnC)
+	case "PredictSpecificClassWithHashedCatFeatures":
+		C.SetPredictSpecificClassWithHashedCatFeaturesFn(l.fns, fnC)
+	case "PredictSpecificClassWithHashedCatFeaturesAndTextFeatures":
+		C.SetPredictSpecificClassWithHashedCatFeaturesAndTextFeaturesFn(l.fns, fnC)
+	case "PredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeatures":
+		C.SetPredictSpecificClassWithHashedCatFeaturesAndTextAndEmbeddingFeaturesFn(l.fns, fnC)
+	case "CalcModelPredictionFlat":
+		C.SetCalcModelPredictionFlatFn(l.fns, fnC)
+	case "CalcModelPredictionFlatTransposed":
+		C.SetCalcModelPredictionFlatTransposedFn(l.fns, fnC)
+	case "GetTreeCount":
+		C.SetGetTreeCountFn(l.fns, fnC)
+	case "CalcModelPredictionStaged":
+		C.SetCalcModelPredictionStagedFn(l.fns, fnC)
+	case "CalcModelPredictionTextStaged":
+		C.SetCalcModelPredictionTextStagedFn(l.fns, fnC)
+	case "CalcModelPredictionSingleStaged":
+		C.SetCalcModelPredictionSingleStagedFn(l.fns, fnC)
+	default:
+		panic(fmt.Sprintf("not supported function from catboost library: %s", fnName))
+	}
+
+	l.symbols[fnName] = true
+
+	return true
+}
+
+// getFromLibraryFn retruns point to function from CatBoost shared memory or nil if function is missing.
+func getFromLibraryFn(handle unsafe.Pointer, fnName string) unsafe.Pointer {
+	cFnName := C.CString(fnName)
+	defer C.free(unsafe.Pointer(cFnName))
+
+	return C.dlsym(handle, cFnName)
+}
@@ -0,0 +1,62 @@
+package catboost
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// LoadFullModelFromMmap returns model loaded from memory-mapped file.
+// Unlike LoadFullModelFromFile with os.ReadFile the file isn't copied into Go memory,
+// it is mapped only while CatBoost loads the model. The file must not be truncated while loading.
+func LoadFullModelFromMmap(filename string) (*Model, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.LoadFullModelFromMmap(filename)
+}
+
+// LoadFullModelFromMmap returns model loaded from memory-mapped file by library.
+func (l *Library) LoadFullModelFromMmap(filename string) (*Model, error) {
+	filename, err := checkModelFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	// File may be truncated after check
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("%w: %w: %s", ErrLoadFullModelFromFile, ErrEmptyModel, filename)
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("%w: mmap: %w", ErrLoadFullModelFromFile, err)
+	}
+	defer syscall.Munmap(data) //nolint:errcheck
+
+	handler, err := l.loadBuffer(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadFullModelFromFile, err)
+	}
+
+	m := newModel(l, handler, nil)
+	m.filename = filename
+	m.mmap = true
+	m.checksum = sha256.Sum256(data)
+
+	return m, nil
+}
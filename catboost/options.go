@@ -0,0 +1,137 @@
+package catboost
+
+import (
+	"context"
+	"fmt"
+)
+
+// PredictOptions are options of a single prediction call.
+type PredictOptions struct {
+	// Type is prediction type, RawFormulaVal by default.
+	Type PredictionType
+}
+
+// PredictWith returns predictions with options of the call.
+// Unlike SetPredictionType it is safe for concurrent use: the prediction type of the model
+// is served by the model itself, any other type uses its own model handle loaded from
+// the same buffer or file on first use. Each such handle is a full copy of the model
+// kept until Close, so memory grows by the model size for every distinct type used.
+func (m *Model) PredictWith(
+	ctx context.Context, opts PredictOptions, floats [][]float32, cats [][]string,
+) ([]float64, error) {
+	model, err := m.withOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.Predict(floats, cats)
+}
+
+// PredictSingleWith returns prediction for a single sample with options of the call.
+func (m *Model) PredictSingleWith(
+	ctx context.Context, opts PredictOptions, floats []float32, cats []string,
+) ([]float64, error) {
+	model, err := m.withOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.PredictSingle(floats, cats)
+}
+
+// PredictTextWith returns predictions for samples with text features with options of the call.
+func (m *Model) PredictTextWith(
+	ctx context.Context, opts PredictOptions, floats [][]float32, cats [][]string, texts [][]string,
+) ([]float64, error) {
+	model, err := m.withOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.PredictText(floats, cats, texts)
+}
+
+// TransformWith change data for result Multiclassification with options of the call.
+func (m *Model) TransformWith(opts PredictOptions, preds []float64) [][]float64 {
+	size := m.GetDimensionsCount()
+	if opts.Type == Class {
+		size = 1
+	}
+
+	return transform(preds, size)
+}
+
+// withOptions returns model with handle for options of the call.
+func (m *Model) withOptions(ctx context.Context, opts PredictOptions) (*Model, error) {
+	if err := m.acquire(); err != nil {
+		return nil, err
+	}
+	defer m.release()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	predictionType := opts.Type
+	if predictionType == "" {
+		predictionType = RawFormulaVal
+	}
+
+	if predictionType == m.predictionType {
+		return m, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if model, ok := m.typed[predictionType]; ok {
+		return model, nil
+	}
+
+	model, err := m.loadAgain()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := model.SetPredictionType(predictionType); err != nil {
+		model.Delete()
+		return nil, err
+	}
+
+	if m.typed == nil {
+		m.typed = make(map[PredictionType]*Model)
+	}
+	m.typed[predictionType] = model
+
+	return model, nil
+}
+
+// loadAgain returns new model handle loaded from the same buffer or file.
+// A model reloaded from file must match the checksum of the parent model,
+// otherwise predictions would be made by a different model if the file was replaced.
+func (m *Model) loadAgain() (*Model, error) {
+	var (
+		model *Model
+		err   error
+	)
+
+	switch {
+	case m.filename != "" && m.mmap:
+		model, err = m.lib.LoadFullModelFromMmap(m.filename)
+	case m.filename != "":
+		model, err = m.lib.LoadFullModelFromFile(m.filename)
+	default:
+		return m.lib.LoadFullModelFromBuffer(m.buffer)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if model.checksum != m.checksum {
+		model.Delete()
+		return nil, fmt.Errorf("%w: %s was modified after loading", ErrChecksumMismatch, m.filename)
+	}
+
+	return model, nil
+}
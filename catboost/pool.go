@@ -0,0 +1,206 @@
+package catboost
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// PoolOptions are options of Pool.
+type PoolOptions struct {
+	// Size is number of model handles in pool, runtime.GOMAXPROCS(0) by default.
+	Size int
+	// Timeout is max time of waiting free model handle, no timeout by default.
+	Timeout time.Duration
+	// Library is library used for loading models, DefaultLibrary by default.
+	Library *Library
+}
+
+// Pool is a pool of model handles loaded from the same buffer.
+// Each handle is used by one goroutine at a time, so Pool is safe for concurrent use.
+type Pool struct {
+	models  chan *Model
+	size    int
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	done   chan struct{}
+
+	// outMu guards out, models checked out by Get and not returned by Put yet.
+	outMu sync.Mutex
+	out   map[*Model]struct{}
+
+	// closeMu serializes closing, deleted is number of models deleted by it.
+	closeMu sync.Mutex
+	deleted int
+}
+
+// NewPoolFromFile returns pool of models loaded from file.
+func NewPoolFromFile(filename string, opts PoolOptions) (*Pool, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	return NewPoolFromBuffer(b, opts)
+}
+
+// NewPoolFromBuffer returns pool of models loaded from memory buffer.
+func NewPoolFromBuffer(buffer []byte, opts PoolOptions) (*Pool, error) {
+	size := opts.Size
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	lib := opts.Library
+	if lib == nil {
+		var err error
+		if lib, err = DefaultLibrary(); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &Pool{
+		models:  make(chan *Model, size),
+		size:    size,
+		timeout: opts.Timeout,
+		done:    make(chan struct{}),
+		out:     make(map[*Model]struct{}, size),
+	}
+
+	for i := 0; i < size; i++ {
+		model, err := lib.LoadFullModelFromBuffer(buffer)
+		if err != nil {
+			for j := 0; j < i; j++ {
+				(<-p.models).Delete()
+			}
+			return nil, err
+		}
+		p.models <- model
+	}
+
+	return p, nil
+}
+
+// Size returns number of model handles in pool.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Get returns free model from pool.
+// The model must be returned to pool by Put and must not be used after that.
+func (p *Pool) Get(ctx context.Context) (*Model, error) {
+	if p.isClosed() {
+		return nil, ErrPoolClosed
+	}
+
+	var timeout <-chan time.Time
+	if p.timeout > 0 {
+		timer := time.NewTimer(p.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case model := <-p.models:
+		if p.isClosed() {
+			p.models <- model
+			return nil, ErrPoolClosed
+		}
+
+		p.outMu.Lock()
+		p.out[model] = struct{}{}
+		p.outMu.Unlock()
+
+		return model, nil
+	case <-p.done:
+		return nil, ErrPoolClosed
+	case <-timeout:
+		return nil, fmt.Errorf("%w: %s", ErrPoolTimeout, p.timeout)
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Put returns model to pool. It panics if the model isn't checked out by Get,
+// e.g. it is already returned or belongs to another pool: that would block the pool forever.
+func (p *Pool) Put(model *Model) {
+	p.outMu.Lock()
+	_, ok := p.out[model]
+	delete(p.out, model)
+	p.outMu.Unlock()
+
+	if !ok {
+		panic("model returned to pool by Put is not checked out from it by Get")
+	}
+
+	p.models <- model
+}
+
+// Do calls fn with free model from pool and returns model to pool after that.
+func (p *Pool) Do(ctx context.Context, fn func(*Model) error) error {
+	model, err := p.Get(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.Put(model)
+
+	return fn(model)
+}
+
+// Predict returns predictions calculated by free model from pool.
+func (p *Pool) Predict(ctx context.Context, floats [][]float32, cats [][]string) ([]float64, error) {
+	var preds []float64
+
+	err := p.Do(ctx, func(model *Model) error {
+		var err error
+		preds, err = model.Predict(floats, cats)
+		return err
+	})
+
+	return preds, err
+}
+
+// Close waits for all models to be returned to pool and deletes them.
+// Get returns ErrPoolClosed after Close.
+// Close blocks forever if a model is never returned by Put, use CloseContext to limit waiting.
+func (p *Pool) Close() {
+	_ = p.CloseContext(context.Background())
+}
+
+// CloseContext is like Close, but stops waiting for models when ctx is done and returns its error.
+// Models returned after that are deleted by next call of CloseContext or by finalizer.
+func (p *Pool) CloseContext(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+	}
+	p.mu.Unlock()
+
+	p.closeMu.Lock()
+	defer p.closeMu.Unlock()
+
+	for p.deleted < p.size {
+		select {
+		case model := <-p.models:
+			model.Delete()
+			p.deleted++
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %d of %d models aren't returned to pool", ctx.Err(), p.size-p.deleted, p.size)
+		}
+	}
+
+	return nil
+}
+
+func (p *Pool) isClosed() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.closed
+}
@@ -0,0 +1,127 @@
+package catboost
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// LoadFullModelFromReader returns model read from r (e.g. entry of tar archive).
+// Gzip and zstd streams (`.cbm.gz`, `.cbm.zst`) are detected by magic bytes and decompressed.
+func LoadFullModelFromReader(r io.Reader) (*Model, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.LoadFullModelFromReader(r)
+}
+
+// LoadFullModelFromFS returns model read from file name of fsys (e.g. embed.FS), see LoadFullModelFromReader.
+// If sidecar file `<name>.sha256` (sha256sum format) exists, it is checked against SHA-256 of uncompressed model
+// as Fingerprint, e.g. `sha256sum model.cbm` made before compression to `model.cbm.gz`.
+func LoadFullModelFromFS(fsys fs.FS, name string) (*Model, error) {
+	lib, err := DefaultLibrary()
+	if err != nil {
+		return nil, err
+	}
+
+	return lib.LoadFullModelFromFS(fsys, name)
+}
+
+// LoadFullModelFromReader returns model read from r by library, see LoadFullModelFromReader.
+func (l *Library) LoadFullModelFromReader(r io.Reader) (*Model, error) {
+	buffer, err := readModel(r)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromBuffer, err)
+	}
+
+	return l.LoadFullModelFromBuffer(buffer)
+}
+
+// LoadFullModelFromFS returns model read from file name of fsys by library, see LoadFullModelFromFS.
+func (l *Library) LoadFullModelFromFS(fsys fs.FS, name string) (*Model, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	buffer, err := readModel(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromBuffer, err)
+	}
+
+	if err := checkSidecarChecksum(fsys, name, buffer); err != nil {
+		return nil, err
+	}
+
+	return l.LoadFullModelFromBuffer(buffer)
+}
+
+// readModel returns content of r, decompressed if it is gzip or zstd stream.
+func readModel(r io.Reader) ([]byte, error) {
+	br := bufio.NewReader(r)
+
+	// Short stream is read as is
+	magic, _ := br.Peek(len(zstdMagic))
+
+	switch {
+	case bytes.HasPrefix(magic, gzipMagic):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		return io.ReadAll(zr)
+	case bytes.HasPrefix(magic, zstdMagic):
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+
+		return io.ReadAll(zr)
+	default:
+		return io.ReadAll(br)
+	}
+}
+
+// checkSidecarChecksum compares sha256 of uncompressed model with sidecar file `<name>.sha256` if it exists.
+func checkSidecarChecksum(fsys fs.FS, name string, data []byte) error {
+	sidecar, err := fs.ReadFile(fsys, name+".sha256")
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	fields := strings.Fields(string(sidecar))
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: empty %s.sha256", ErrChecksumMismatch, name)
+	}
+
+	sum := sha256.Sum256(data)
+	expected, actual := strings.ToLower(fields[0]), hex.EncodeToString(sum[:])
+
+	if expected != actual {
+		return fmt.Errorf("%w: %s: expected %s, got %s", ErrChecksumMismatch, name, expected, actual)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package catboost
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RecordError is an error of record which does not match features of the model.
+// Use errors.Is(err, ErrInvalidRecord) for check.
+type RecordError struct {
+	// Row is index of record.
+	Row int
+	// Unknown are names of record which are not features of the model.
+	Unknown []string
+	// Missing are names of model features absent in record.
+	Missing []string
+	// Feature is name of feature with value of unsupported type, Kind is its kind.
+	Feature string
+	Kind    FeatureKind
+	Value   any
+}
+
+// Error returns message of error.
+func (e *RecordError) Error() string {
+	var reasons []string
+
+	if len(e.Missing) > 0 {
+		reasons = append(reasons, fmt.Sprintf("missing features %q", e.Missing))
+	}
+	if len(e.Unknown) > 0 {
+		reasons = append(reasons, fmt.Sprintf("unknown features %q", e.Unknown))
+	}
+	if e.Feature != "" {
+		reasons = append(reasons, fmt.Sprintf("invalid value %v (%T) of %s feature %q", e.Value, e.Value, e.Kind, e.Feature))
+	}
+
+	return fmt.Sprintf("%s in row %d: %s", ErrInvalidRecord, e.Row, strings.Join(reasons, ", "))
+}
+
+// Unwrap returns ErrInvalidRecord.
+func (e *RecordError) Unwrap() error {
+	return ErrInvalidRecord
+}
+
+// featureSlot is position of named feature in slice of its kind.
+type featureSlot struct {
+	kind  FeatureKind
+	index int
+}
+
+// featureLayout maps names of model features to positions in float, cat and text slices.
+type featureLayout struct {
+	schema *Schema
+	slots  map[string]featureSlot
+	floats int
+	cats   int
+	texts  int
+}
+
+// PredictRecords returns predictions for records of feature name to value.
+// Values of float features are Go numbers, values of cat features are strings or numbers
+// (converted to strings in decimal form), values of text features are strings.
+// All features of the model must be present, see RecordError.
+// Models with embedding features are not supported.
+func (m *Model) PredictRecords(records []map[string]any) ([]float64, error) {
+	layout, err := m.featureLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return []float64{}, nil
+	}
+
+	floats := make([][]float32, len(records))
+	cats := make([][]string, len(records))
+	texts := make([][]string, len(records))
+
+	for i, record := range records {
+		floats[i] = make([]float32, layout.floats)
+		cats[i] = make([]string, layout.cats)
+		texts[i] = make([]string, layout.texts)
+
+		if err := layout.fill(i, record, floats[i], cats[i], texts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.predictFeatures(layout, floats, cats, texts)
+}
+
+// predictFeatures returns predictions for rows of features built by layout.
+func (m *Model) predictFeatures(layout *featureLayout, floats [][]float32, cats, texts [][]string) ([]float64, error) {
+	if layout.texts > 0 {
+		return m.PredictText(floats, cats, texts)
+	}
+
+	return m.Predict(floats, cats)
+}
+
+// featureLayout returns layout of model features, it is loaded once per model.
+func (m *Model) featureLayout() (*featureLayout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.layout != nil {
+		return m.layout, nil
+	}
+
+	schema, err := m.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schema.Names(EmbeddingFeature)) > 0 {
+		return nil, fmt.Errorf("%w: embedding features are not supported for named input", ErrInvalidRecord)
+	}
+
+	layout := &featureLayout{schema: schema, slots: make(map[string]featureSlot, len(schema.Features))}
+
+	for _, k := range []struct {
+		kind  FeatureKind
+		count *int
+	}{
+		{FloatFeature, &layout.floats},
+		{CatFeature, &layout.cats},
+		{TextFeature, &layout.texts},
+	} {
+		names := schema.Names(k.kind)
+		for i, name := range names {
+			layout.slots[name] = featureSlot{kind: k.kind, index: i}
+		}
+		*k.count = len(names)
+	}
+
+	m.layout = layout
+
+	return layout, nil
+}
+
+// fill sets values of record into rows of features.
+func (l *featureLayout) fill(row int, record map[string]any, floats []float32, cats, texts []string) error {
+	var unknown []string
+	for name := range record {
+		if _, ok := l.slots[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+
+	var missing []string
+	for name := range l.slots {
+		if _, ok := record[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(unknown) > 0 || len(missing) > 0 {
+		sort.Strings(unknown)
+		sort.Strings(missing)
+		return &RecordError{Row: row, Unknown: unknown, Missing: missing}
+	}
+
+	for name, value := range record {
+		slot := l.slots[name]
+
+		var ok bool
+		switch slot.kind {
+		case FloatFeature:
+			floats[slot.index], ok = toFloat32(value)
+		case CatFeature:
+			cats[slot.index], ok = toCatString(value)
+		case TextFeature:
+			texts[slot.index], ok = value.(string)
+		}
+
+		if !ok {
+			return &RecordError{Row: row, Feature: name, Kind: slot.kind, Value: value}
+		}
+	}
+
+	return nil
+}
+
+// toFloat32 converts Go number to float32.
+func toFloat32(value any) (float32, bool) {
+	switch v := value.(type) {
+	case float32:
+		return v, true
+	case float64:
+		return float32(v), true
+	case int:
+		return float32(v), true
+	case int8:
+		return float32(v), true
+	case int16:
+		return float32(v), true
+	case int32:
+		return float32(v), true
+	case int64:
+		return float32(v), true
+	case uint:
+		return float32(v), true
+	case uint8:
+		return float32(v), true
+	case uint16:
+		return float32(v), true
+	case uint32:
+		return float32(v), true
+	case uint64:
+		return float32(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toCatString converts string or Go number to value of categorical feature.
+func toCatString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,458 @@
+package catboost
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Latest is version alias resolved to the greatest version of model.
+const Latest = "latest"
+
+// RegistryOptions are options of Registry.
+type RegistryOptions struct {
+	// Library is library used for loading models, DefaultLibrary by default.
+	Library *Library
+	// ModelFile is file name of model in version directory, "model.cbm" by default.
+	ModelFile string
+}
+
+// ModelInfo describes model of Registry.
+type ModelInfo struct {
+	Name    string
+	Version string
+	Path    string
+	// GUID is model metadata MetaModelGUID.
+	GUID string
+	// TrainFinishTime is model metadata MetaTrainFinishTime, zero if it is missing.
+	TrainFinishTime time.Time
+}
+
+// Registry is a set of models loaded from directory with layout `name/version/model.cbm`.
+// Models are referenced as `name` or `name@latest` (the greatest version), `name@version`
+// or `name@alias` (see SetAlias). Safe for concurrent use.
+type Registry struct {
+	dir       string
+	lib       *Library
+	modelFile string
+
+	mu      sync.RWMutex
+	models  map[string]map[string]*registryEntry
+	aliases map[string]map[string]string
+	closed  bool
+}
+
+type registryEntry struct {
+	info  ModelInfo
+	model *Model
+}
+
+// OpenRegistry loads all models from dir.
+func OpenRegistry(dir string, opts RegistryOptions) (*Registry, error) {
+	lib := opts.Library
+	if lib == nil {
+		var err error
+		if lib, err = DefaultLibrary(); err != nil {
+			return nil, err
+		}
+	}
+
+	modelFile := opts.ModelFile
+	if modelFile == "" {
+		modelFile = "model.cbm"
+	}
+
+	r := &Registry{
+		dir:       dir,
+		lib:       lib,
+		modelFile: modelFile,
+		models:    make(map[string]map[string]*registryEntry),
+		aliases:   make(map[string]map[string]string),
+	}
+
+	if err := r.Refresh(); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Get returns model by reference. The model is closed if it is evicted,
+// calls in progress finish before that and later calls return ErrModelClosed.
+// Don't keep the result: resolve reference per call or use Do.
+func (r *Registry) Get(ref string) (*Model, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, err := r.resolve(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.model, nil
+}
+
+// Do calls fn with model by reference. If the model is evicted during the call and fn
+// returns ErrModelClosed, reference is resolved again and fn is called with new model.
+func (r *Registry) Do(ref string, fn func(*Model) error) error {
+	for {
+		model, err := r.Get(ref)
+		if err != nil {
+			return err
+		}
+
+		err = fn(model)
+		if errors.Is(err, ErrModelClosed) && r.evicted(ref, model) {
+			continue
+		}
+
+		return err
+	}
+}
+
+// Info returns description of model by reference.
+func (r *Registry) Info(ref string) (ModelInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entry, err := r.resolve(ref)
+	if err != nil {
+		return ModelInfo{}, err
+	}
+
+	return entry.info, nil
+}
+
+// List returns descriptions of all models sorted by name and version.
+func (r *Registry) List() []ModelInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var infos []ModelInfo
+	for _, versions := range r.models {
+		for _, entry := range versions {
+			infos = append(infos, entry.info)
+		}
+	}
+
+	sort.Slice(infos, func(i, j int) bool {
+		if infos[i].Name != infos[j].Name {
+			return infos[i].Name < infos[j].Name
+		}
+		return compareVersions(infos[i].Version, infos[j].Version) < 0
+	})
+
+	return infos
+}
+
+// SetAlias makes alias (e.g. prod or canary) reference version of model name.
+func (r *Registry) SetAlias(name, alias, version string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if alias == Latest {
+		return fmt.Errorf("%w: %s is reserved", ErrInvalidAlias, Latest)
+	}
+
+	if _, ok := r.models[name][version]; !ok {
+		return fmt.Errorf("%w: %s@%s", ErrModelNotFound, name, version)
+	}
+
+	if r.aliases[name] == nil {
+		r.aliases[name] = make(map[string]string)
+	}
+	r.aliases[name][alias] = version
+
+	return nil
+}
+
+// RemoveAlias removes alias of model name.
+func (r *Registry) RemoveAlias(name, alias string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.aliases[name], alias)
+}
+
+// Refresh loads new models from directory and evicts models removed from it.
+// Models failed to load are skipped, their errors are returned joined.
+func (r *Registry) Refresh() error {
+	found, err := r.scan()
+	if err != nil {
+		return err
+	}
+
+	type key struct{ name, version string }
+
+	// Models are loaded without lock, so Get isn't blocked by loading
+	r.mu.RLock()
+	var missing []key
+	for name, versions := range found {
+		for version := range versions {
+			if _, ok := r.models[name][version]; !ok {
+				missing = append(missing, key{name, version})
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	var errs []error
+
+	loaded := make(map[key]*registryEntry, len(missing))
+	for _, k := range missing {
+		entry, err := r.load(k.name, k.version, found[k.name][k.version])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s@%s: %w", k.name, k.version, err))
+			continue
+		}
+		loaded[k] = entry
+	}
+
+	var evicted []*Model
+
+	r.mu.Lock()
+
+	if r.closed {
+		r.mu.Unlock()
+		for _, entry := range loaded {
+			evicted = append(evicted, entry.model)
+		}
+		closeModels(evicted)
+		return ErrRegistryClosed
+	}
+
+	for k, entry := range loaded {
+		// Concurrent Refresh may load the same model
+		if _, ok := r.models[k.name][k.version]; ok {
+			evicted = append(evicted, entry.model)
+			continue
+		}
+
+		if r.models[k.name] == nil {
+			r.models[k.name] = make(map[string]*registryEntry)
+		}
+		r.models[k.name][k.version] = entry
+	}
+
+	for name, versions := range r.models {
+		for version := range versions {
+			if _, ok := found[name][version]; !ok {
+				evicted = append(evicted, r.evict(name, version))
+			}
+		}
+	}
+
+	r.mu.Unlock()
+
+	// Close waits for calls in progress, so it isn't called under lock
+	closeModels(evicted)
+
+	return errors.Join(errs...)
+}
+
+// Evict removes version of model name from registry and closes it.
+func (r *Registry) Evict(name, version string) error {
+	r.mu.Lock()
+
+	if _, ok := r.models[name][version]; !ok {
+		r.mu.Unlock()
+		return fmt.Errorf("%w: %s@%s", ErrModelNotFound, name, version)
+	}
+
+	model := r.evict(name, version)
+	r.mu.Unlock()
+
+	closeModels([]*Model{model})
+
+	return nil
+}
+
+// Close closes all models of registry.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+
+	r.closed = true
+
+	var evicted []*Model
+	for name, versions := range r.models {
+		for version := range versions {
+			evicted = append(evicted, r.evict(name, version))
+		}
+	}
+
+	r.mu.Unlock()
+
+	closeModels(evicted)
+
+	return nil
+}
+
+// resolve returns entry by reference, r.mu must be held.
+func (r *Registry) resolve(ref string) (*registryEntry, error) {
+	name, version, ok := strings.Cut(ref, "@")
+	if !ok || version == "" {
+		version = Latest
+	}
+
+	versions := r.models[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrModelNotFound, ref)
+	}
+
+	if aliased, ok := r.aliases[name][version]; ok {
+		version = aliased
+	}
+
+	if version == Latest {
+		for v := range versions {
+			if version == Latest || compareVersions(v, version) > 0 {
+				version = v
+			}
+		}
+	}
+
+	entry, ok := versions[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrModelNotFound, ref)
+	}
+
+	return entry, nil
+}
+
+// evicted reports whether reference no longer resolves to model in open registry.
+func (r *Registry) evicted(ref string, model *Model) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.closed {
+		return false
+	}
+
+	entry, err := r.resolve(ref)
+
+	return err != nil || entry.model != model
+}
+
+// evict removes model from registry and returns it to be closed without lock, r.mu must be held.
+func (r *Registry) evict(name, version string) *Model {
+	entry := r.models[name][version]
+
+	delete(r.models[name], version)
+	if len(r.models[name]) == 0 {
+		delete(r.models, name)
+	}
+
+	for alias, v := range r.aliases[name] {
+		if v == version {
+			delete(r.aliases[name], alias)
+		}
+	}
+
+	return entry.model
+}
+
+// closeModels closes models, waiting for calls in progress on them.
+func closeModels(models []*Model) {
+	for _, model := range models {
+		_ = model.Close()
+	}
+}
+
+// scan returns paths of model files by name and version.
+func (r *Registry) scan() (map[string]map[string]string, error) {
+	names, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	found := make(map[string]map[string]string)
+
+	for _, name := range names {
+		if !name.IsDir() {
+			continue
+		}
+
+		versions, err := os.ReadDir(filepath.Join(r.dir, name.Name()))
+		if err != nil {
+			return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+		}
+
+		for _, version := range versions {
+			path := filepath.Join(r.dir, name.Name(), version.Name(), r.modelFile)
+			if _, err := os.Stat(path); err != nil {
+				continue
+			}
+
+			if found[name.Name()] == nil {
+				found[name.Name()] = make(map[string]string)
+			}
+			found[name.Name()][version.Name()] = path
+		}
+	}
+
+	return found, nil
+}
+
+func (r *Registry) load(name, version, path string) (*registryEntry, error) {
+	model, err := r.lib.LoadFullModelFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := ModelInfo{
+		Name:    name,
+		Version: version,
+		Path:    path,
+		GUID:    model.GetModelInfoValue(MetaModelGUID),
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, model.GetModelInfoValue(MetaTrainFinishTime)); err == nil {
+		info.TrainFinishTime = t
+	}
+
+	return &registryEntry{info: info, model: model}, nil
+}
+
+// compareVersions compares versions in natural order, e.g. v2 < v10.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		var chunkA, chunkB string
+		chunkA, a = versionChunk(a)
+		chunkB, b = versionChunk(b)
+
+		numA, errA := strconv.ParseUint(chunkA, 10, 64)
+		numB, errB := strconv.ParseUint(chunkB, 10, 64)
+
+		switch {
+		case errA == nil && errB == nil && numA != numB:
+			if numA < numB {
+				return -1
+			}
+			return 1
+		case chunkA != chunkB:
+			return strings.Compare(chunkA, chunkB)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
+
+// versionChunk returns leading run of digits or non-digits of version and the rest.
+func versionChunk(version string) (string, string) {
+	isDigit := unicode.IsDigit(rune(version[0]))
+
+	i := 1
+	for i < len(version) && unicode.IsDigit(rune(version[i])) == isDigit {
+		i++
+	}
+
+	return version[:i], version[i:]
+}
@@ -0,0 +1,281 @@
+package catboost
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReloadOptions are options of ReloadableModel.
+type ReloadOptions struct {
+	// Interval is polling interval of model file, 10 seconds by default.
+	Interval time.Duration
+	// Library is library used for loading models, DefaultLibrary by default.
+	Library *Library
+	// AllowSchemaChange disables check that new model has the same features as current one.
+	AllowSchemaChange bool
+	// OnReload is called after each reload attempt, it must not block.
+	OnReload func(ReloadEvent)
+	// Configure is called with initial model and every reloaded model before it is swapped in,
+	// e.g. to set prediction type. If it fails, reloaded model is rejected.
+	Configure func(*Model) error
+}
+
+// ReloadEvent is a result of reload attempt.
+type ReloadEvent struct {
+	Filename string
+	// ModTime is modification time of loaded file.
+	ModTime time.Time
+	// Err is nil if new model is swapped in, current model is kept otherwise.
+	Err error
+}
+
+// ReloadableModel is a model reloaded when its file changes.
+// New model replaces current one atomically: calls in progress finish on the old model,
+// which is closed after that. Safe for concurrent use.
+type ReloadableModel struct {
+	filename string
+	lib      *Library
+	opts     ReloadOptions
+
+	current atomic.Pointer[Model]
+	closed  atomic.Bool
+
+	// mu serializes reloads, modTime and size are of the last attempted file.
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewReloadableModel loads model from file and starts watching the file for changes.
+func NewReloadableModel(filename string, opts ReloadOptions) (*ReloadableModel, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+
+	lib := opts.Library
+	if lib == nil {
+		var err error
+		if lib, err = DefaultLibrary(); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err)
+	}
+
+	model, err := lib.LoadFullModelFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := configure(model, opts.Configure); err != nil {
+		return nil, err
+	}
+
+	r := &ReloadableModel{
+		filename: filename,
+		lib:      lib,
+		opts:     opts,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	r.current.Store(model)
+
+	go r.watch()
+
+	return r, nil
+}
+
+// Do calls fn with current model. If the model is swapped during the call and fn
+// returns ErrModelClosed, fn is called again with new model.
+func (r *ReloadableModel) Do(fn func(*Model) error) error {
+	for {
+		model := r.current.Load()
+
+		err := fn(model)
+		if errors.Is(err, ErrModelClosed) && !r.closed.Load() && r.current.Load() != model {
+			continue
+		}
+
+		return err
+	}
+}
+
+// Predict returns predictions of current model.
+func (r *ReloadableModel) Predict(floats [][]float32, cats [][]string) ([]float64, error) {
+	var preds []float64
+
+	err := r.Do(func(model *Model) error {
+		var err error
+		preds, err = model.Predict(floats, cats)
+		return err
+	})
+
+	return preds, err
+}
+
+// Reload loads model file and swaps current model regardless of file changes.
+func (r *ReloadableModel) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.filename)
+	if err != nil {
+		return r.notify(time.Time{}, fmt.Errorf(formatErrorMessage, ErrLoadFullModelFromFile, err))
+	}
+
+	return r.reload(info)
+}
+
+// Close stops watching the file and closes current model.
+func (r *ReloadableModel) Close() error {
+	r.closeOnce.Do(func() {
+		r.closed.Store(true)
+		close(r.stop)
+		<-r.done
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current.Load().Close()
+}
+
+// watch polls the file and reloads model if modification time or size are changed.
+func (r *ReloadableModel) watch() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+			r.reloadIfChanged()
+		}
+	}
+}
+
+func (r *ReloadableModel) reloadIfChanged() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, err := os.Stat(r.filename)
+	if err != nil {
+		// File may be missing while it is replaced, it is checked again on next tick
+		return
+	}
+
+	if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return
+	}
+
+	_ = r.reload(info)
+}
+
+// reload loads model from file of info and swaps it with current one, r.mu must be held.
+func (r *ReloadableModel) reload(info os.FileInfo) error {
+	// Failed file is not retried until it is changed again
+	r.modTime, r.size = info.ModTime(), info.Size()
+
+	if r.closed.Load() {
+		return r.notify(info.ModTime(), ErrModelClosed)
+	}
+
+	model, err := r.lib.LoadFullModelFromFile(r.filename)
+	if err != nil {
+		return r.notify(info.ModTime(), err)
+	}
+
+	if err := configure(model, r.opts.Configure); err != nil {
+		return r.notify(info.ModTime(), err)
+	}
+
+	old := r.current.Load()
+
+	if !r.opts.AllowSchemaChange {
+		if err := checkSameSchema(old, model); err != nil {
+			model.Delete()
+			return r.notify(info.ModTime(), err)
+		}
+	}
+
+	r.current.Store(model)
+
+	// Close waits for calls in progress on the old model
+	_ = old.Close()
+
+	return r.notify(info.ModTime(), nil)
+}
+
+// configure calls fn with model, the model is deleted if fn fails.
+func configure(model *Model, fn func(*Model) error) error {
+	if fn == nil {
+		return nil
+	}
+
+	if err := fn(model); err != nil {
+		model.Delete()
+		return err
+	}
+
+	return nil
+}
+
+func (r *ReloadableModel) notify(modTime time.Time, err error) error {
+	if r.opts.OnReload != nil {
+		r.opts.OnReload(ReloadEvent{Filename: r.filename, ModTime: modTime, Err: err})
+	}
+
+	return err
+}
+
+// checkSameSchema returns ErrSchemaMismatch if models have different features or dimensions.
+func checkSameSchema(old, model *Model) error {
+	counts := func(m *Model) []int {
+		return []int{
+			m.GetFloatFeaturesCount(),
+			m.GetCatFeaturesCount(),
+			m.GetTextFeaturesCount(),
+			m.GetEmbeddingFeaturesCount(),
+			m.GetDimensionsCount(),
+		}
+	}
+
+	oldCounts, newCounts := counts(old), counts(model)
+	if !slices.Equal(oldCounts, newCounts) {
+		return fmt.Errorf("%w: counts of float, cat, text, embedding features and dimensions %v, expected %v",
+			ErrSchemaMismatch, newCounts, oldCounts)
+	}
+
+	oldNames, err := old.GetModelUsedFeaturesNames()
+	if err != nil {
+		return err
+	}
+
+	newNames, err := model.GetModelUsedFeaturesNames()
+	if err != nil {
+		return err
+	}
+
+	if !slices.Equal(oldNames, newNames) {
+		return fmt.Errorf("%w: features %v, expected %v", ErrSchemaMismatch, newNames, oldNames)
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package catboost
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Schema describes inputs and outputs of model.
+type Schema struct {
+	// Features are features of model ordered by flat index.
+	Features []FeatureSchema `json:"features"`
+	// Dimensions is number of dimensions of prediction (number of classes for multiclassification).
+	Dimensions int `json:"dimensions"`
+	// ClassLabels are labels of classes for classification, empty otherwise.
+	ClassLabels []string `json:"class_labels,omitempty"`
+}
+
+// FeatureSchema describes feature of model.
+type FeatureSchema struct {
+	Name string      `json:"name"`
+	Kind FeatureKind `json:"kind"`
+	// Index is flat index of feature among all features of model.
+	Index int `json:"index"`
+}
+
+// Schema returns description of features and outputs of model.
+// Dimensions of embedding features aren't described: CatBoost C API doesn't expose them.
+func (m *Model) Schema() (*Schema, error) {
+	names, err := m.GetModelUsedFeaturesNames()
+	if err != nil {
+		return nil, err
+	}
+
+	features := make([]FeatureSchema, len(names))
+	for i, name := range names {
+		features[i] = FeatureSchema{Name: name, Index: i}
+	}
+
+	kinds := []struct {
+		kind    FeatureKind
+		indices func() ([]uint64, error)
+	}{
+		{FloatFeature, m.GetFloatFeatureIndices},
+		{CatFeature, m.GetCatFeatureIndices},
+		{TextFeature, m.GetTextFeatureIndices},
+		{EmbeddingFeature, m.GetEmbeddingFeatureIndices},
+	}
+
+	for _, k := range kinds {
+		// Libraries without text or embedding features support return ErrNotSupportedByLibrary
+		if k.kind == TextFeature && m.GetTextFeaturesCount() == 0 ||
+			k.kind == EmbeddingFeature && m.GetEmbeddingFeaturesCount() == 0 {
+			continue
+		}
+
+		indices, err := k.indices()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, index := range indices {
+			if index >= uint64(len(names)) {
+				return nil, fmt.Errorf("%w: index %d of %s feature, got %d names", ErrGetIndices, index, k.kind, len(names))
+			}
+			features[index].Kind = k.kind
+		}
+	}
+
+	for _, f := range features {
+		if f.Kind == "" {
+			return nil, fmt.Errorf("%w: unknown kind of feature %q", ErrGetIndices, f.Name)
+		}
+	}
+
+	classLabels, err := m.classLabels()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{
+		Features:    features,
+		Dimensions:  m.GetDimensionsCount(),
+		ClassLabels: classLabels,
+	}, nil
+}
+
+// Names returns names of features of kind in order of their slice in Predict methods.
+func (s *Schema) Names(kind FeatureKind) []string {
+	var names []string
+	for _, f := range s.Features {
+		if f.Kind == kind {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}
+
+// classLabels returns labels of classes from model metadata MetaClassParams.
+func (m *Model) classLabels() ([]string, error) {
+	value := m.GetModelInfoValue(MetaClassParams)
+	if value == "" {
+		return nil, nil
+	}
+
+	var params struct {
+		ClassNames []json.RawMessage `json:"class_names"`
+	}
+
+	if err := json.Unmarshal([]byte(value), &params); err != nil {
+		return nil, fmt.Errorf("invalid %s of model: %w", MetaClassParams, err)
+	}
+
+	if len(params.ClassNames) == 0 {
+		return nil, nil
+	}
+
+	labels := make([]string, 0, len(params.ClassNames))
+	for _, raw := range params.ClassNames {
+		var label string
+		if err := json.Unmarshal(raw, &label); err != nil {
+			// Integer and float labels are kept as in JSON
+			label = strings.TrimSpace(string(raw))
+		}
+		labels = append(labels, label)
+	}
+
+	return labels, nil
+}
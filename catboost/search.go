@@ -0,0 +1,156 @@
+package catboost
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultLibrarySearchPaths are directories searched for CatBoost shared library by default.
+// `$ORIGIN` is directory of executable, environment variables are expanded
+// and may contain list of directories (e.g. LD_LIBRARY_PATH).
+var DefaultLibrarySearchPaths = []string{
+	"$LD_LIBRARY_PATH",
+	"$DYLD_LIBRARY_PATH",
+	"$ORIGIN",
+	"$ORIGIN/lib",
+	"/usr/local/lib",
+}
+
+var librarySearchPaths = DefaultLibrarySearchPaths
+
+// SetLibrarySearchPaths sets directories searched for CatBoost shared library in order,
+// see DefaultLibrarySearchPaths. It has effect only before the library is loaded by first model.
+func SetLibrarySearchPaths(paths ...string) {
+	defaultLibraryMu.Lock()
+	defer defaultLibraryMu.Unlock()
+
+	librarySearchPaths = paths
+}
+
+// LibraryCandidate is a path tried while searching CatBoost shared library.
+type LibraryCandidate struct {
+	Path string
+	// Err is reason why the path was rejected.
+	Err error
+}
+
+// LibraryNotFoundError is an error of searching CatBoost shared library with all tried candidates.
+// Use errors.Is(err, ErrNotFoundLibrary) for check.
+type LibraryNotFoundError struct {
+	Candidates []LibraryCandidate
+}
+
+// Error returns message of error.
+func (e *LibraryNotFoundError) Error() string {
+	var b strings.Builder
+
+	b.WriteString(ErrNotFoundLibrary.Error())
+
+	if len(e.Candidates) == 0 {
+		b.WriteString(": no candidates, set path by SetSharedLibraryPath or CATBOOST_LIBRARY_PATH")
+		return b.String()
+	}
+
+	b.WriteString(", tried:")
+	for _, c := range e.Candidates {
+		fmt.Fprintf(&b, "\n\t%s: %v", c.Path, c.Err)
+	}
+
+	return b.String()
+}
+
+// Unwrap returns ErrNotFoundLibrary.
+func (e *LibraryNotFoundError) Unwrap() error {
+	return ErrNotFoundLibrary
+}
+
+// findLibrary opens first loadable candidate of CatBoost shared library.
+// Path from SetSharedLibraryPath or CATBOOST_LIBRARY_PATH is the only candidate if it is set.
+func findLibrary() (*Library, error) {
+	var candidates []LibraryCandidate
+
+	for _, path := range libraryCandidates() {
+		if _, err := os.Stat(path); err != nil {
+			var pathErr *fs.PathError
+			if errors.As(err, &pathErr) {
+				err = pathErr.Err
+			}
+
+			candidates = append(candidates, LibraryCandidate{Path: path, Err: err})
+			continue
+		}
+
+		lib, err := OpenLibrary(path)
+		if err == nil {
+			return lib, nil
+		}
+
+		candidates = append(candidates, LibraryCandidate{Path: path, Err: err})
+	}
+
+	return nil, &LibraryNotFoundError{Candidates: candidates}
+}
+
+// libraryCandidates returns paths of CatBoost shared library in search order without duplicates.
+func libraryCandidates() []string {
+	if catboostSharedLibraryPath != "" {
+		return []string{catboostSharedLibraryPath}
+	}
+
+	if path := os.Getenv("CATBOOST_LIBRARY_PATH"); path != "" {
+		return []string{path}
+	}
+
+	var origin string
+	if executable, err := os.Executable(); err == nil {
+		origin = filepath.Dir(executable)
+	}
+
+	var candidates []string
+	seen := make(map[string]bool)
+
+	for _, searchPath := range librarySearchPaths {
+		noOrigin := false
+		expanded := os.Expand(searchPath, func(name string) string {
+			if name == "ORIGIN" {
+				noOrigin = origin == ""
+				return origin
+			}
+			return os.Getenv(name)
+		})
+
+		if noOrigin {
+			continue
+		}
+
+		for _, dir := range filepath.SplitList(expanded) {
+			if dir == "" {
+				continue
+			}
+
+			for _, name := range libraryNames() {
+				path := filepath.Join(dir, name)
+				if !seen[path] {
+					seen[path] = true
+					candidates = append(candidates, path)
+				}
+			}
+		}
+	}
+
+	return candidates
+}
+
+// libraryNames returns file names of CatBoost shared library including versioned sonames.
+func libraryNames() []string {
+	if runtime.GOOS == "darwin" {
+		return []string{"libcatboostmodel.dylib", "libcatboostmodel.1.dylib"}
+	}
+
+	return []string{"libcatboostmodel.so", "libcatboostmodel.so.1"}
+}
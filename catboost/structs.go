@@ -0,0 +1,278 @@
+package catboost
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FeatureEncoder is implemented by input structs generated by catboostgen,
+// PredictStructs uses it instead of reflection.
+type FeatureEncoder interface {
+	// FeatureNames returns names of float, cat and text features in order of EncodeFeatures.
+	FeatureNames() (floats, cats, texts []string)
+	// EncodeFeatures sets features into slices of model feature counts.
+	EncodeFeatures(floats []float32, cats, texts []string)
+}
+
+// structBinding maps fields of struct type to positions of model features.
+type structBinding struct {
+	// encoder is true if pointer to struct implements FeatureEncoder.
+	encoder bool
+	fields  []fieldBinding
+}
+
+type fieldBinding struct {
+	index []int
+	slot  featureSlot
+}
+
+// PredictStructs returns predictions for rows of struct (or pointer to struct) type T.
+// Fields are bound to model features by tags `catboost:"Age"` or `catboost:"Sex,cat"`,
+// kind of feature (float, cat or text) in tag is optional and checked against the model.
+// Float features are numeric fields, cat features are string or numeric fields (converted
+// to strings in decimal form), text features are string fields. Every model feature must be
+// bound, otherwise ErrSchemaMismatch is returned. Types generated by catboostgen implement
+// FeatureEncoder and are encoded without reflection.
+func PredictStructs[T any](m *Model, rows []T) ([]float64, error) {
+	layout, err := m.featureLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := m.structBinding(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return []float64{}, nil
+	}
+
+	floats := make([][]float32, len(rows))
+	cats := make([][]string, len(rows))
+	texts := make([][]string, len(rows))
+
+	for i := range rows {
+		floats[i] = make([]float32, layout.floats)
+		cats[i] = make([]string, layout.cats)
+		texts[i] = make([]string, layout.texts)
+
+		if binding.encoder {
+			encoder, ok := any(rows[i]).(FeatureEncoder)
+			if !ok {
+				encoder = any(&rows[i]).(FeatureEncoder)
+			}
+
+			if value := reflect.ValueOf(encoder); value.Kind() == reflect.Pointer && value.IsNil() {
+				return nil, fmt.Errorf("%w: nil row %d", ErrInvalidRecord, i)
+			}
+
+			encoder.EncodeFeatures(floats[i], cats[i], texts[i])
+			continue
+		}
+
+		if err := binding.encode(i, reflect.ValueOf(&rows[i]).Elem(), floats[i], cats[i], texts[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return m.predictFeatures(layout, floats, cats, texts)
+}
+
+// structBinding returns binding of struct type to model features, it is built once per type.
+func (m *Model) structBinding(t reflect.Type) (*structBinding, error) {
+	layout, err := m.featureLayout()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if binding, ok := m.bindings[t]; ok {
+		return binding, nil
+	}
+
+	var binding *structBinding
+
+	if names, ok := featureNames(t); ok {
+		if err := layout.checkNames(names); err != nil {
+			return nil, err
+		}
+		binding = &structBinding{encoder: true}
+	} else {
+		if binding, err = bindStruct(t, layout); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.bindings == nil {
+		m.bindings = make(map[reflect.Type]*structBinding)
+	}
+	m.bindings[t] = binding
+
+	return binding, nil
+}
+
+// featureNames returns names of features of FeatureEncoder implemented by t or pointer to t.
+func featureNames(t reflect.Type) ([][]string, bool) {
+	encoderType := reflect.TypeFor[FeatureEncoder]()
+
+	var value reflect.Value
+	switch {
+	case t.Kind() == reflect.Pointer && t.Implements(encoderType):
+		value = reflect.New(t.Elem())
+	case reflect.PointerTo(t).Implements(encoderType):
+		value = reflect.New(t)
+	default:
+		return nil, false
+	}
+
+	floats, cats, texts := value.Interface().(FeatureEncoder).FeatureNames()
+
+	return [][]string{floats, cats, texts}, true
+}
+
+// checkNames returns ErrSchemaMismatch if names of float, cat and text features differ from layout.
+func (l *featureLayout) checkNames(names [][]string) error {
+	for i, kind := range []FeatureKind{FloatFeature, CatFeature, TextFeature} {
+		if expected := l.schema.Names(kind); !slices.Equal(names[i], expected) {
+			return fmt.Errorf("%w: %s features %q, expected %q", ErrSchemaMismatch, kind, names[i], expected)
+		}
+	}
+
+	return nil
+}
+
+// bindStruct binds tagged fields of struct type t (or pointer to struct) to features of layout.
+func bindStruct(t reflect.Type, layout *featureLayout) (*structBinding, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidRecord, t)
+	}
+
+	binding := &structBinding{}
+	bound := make(map[string]string)
+
+	for _, field := range reflect.VisibleFields(t) {
+		tag, ok := field.Tag.Lookup("catboost")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		name, kind, _ := strings.Cut(tag, ",")
+
+		slot, ok := layout.slots[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %s.%s is bound to unknown feature %q", ErrSchemaMismatch, t, field.Name, name)
+		}
+
+		if kind != "" && FeatureKind(kind) != slot.kind {
+			return nil, fmt.Errorf("%w: field %s.%s is bound to %s feature %q, model has %s feature",
+				ErrSchemaMismatch, t, field.Name, kind, name, slot.kind)
+		}
+
+		if !field.IsExported() || !supportsKind(field.Type, slot.kind) {
+			return nil, fmt.Errorf("%w: field %s.%s of type %s can't be %s feature %q",
+				ErrSchemaMismatch, t, field.Name, field.Type, slot.kind, name)
+		}
+
+		if other, ok := bound[name]; ok {
+			return nil, fmt.Errorf("%w: fields %s.%s and %s.%s are bound to feature %q",
+				ErrSchemaMismatch, t, other, t, field.Name, name)
+		}
+		bound[name] = field.Name
+
+		binding.fields = append(binding.fields, fieldBinding{index: field.Index, slot: slot})
+	}
+
+	var missing []string
+	for name := range layout.slots {
+		if _, ok := bound[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return nil, fmt.Errorf("%w: features %q are not bound to fields of %s", ErrSchemaMismatch, missing, t)
+	}
+
+	return binding, nil
+}
+
+// supportsKind returns true if value of type t can be feature of kind.
+func supportsKind(t reflect.Type, kind FeatureKind) bool {
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return kind == FloatFeature || kind == CatFeature
+	case reflect.String:
+		return kind == CatFeature || kind == TextFeature
+	default:
+		return false
+	}
+}
+
+// encode sets fields of struct value v into rows of features.
+func (b *structBinding) encode(row int, v reflect.Value, floats []float32, cats, texts []string) error {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return fmt.Errorf("%w: nil row %d", ErrInvalidRecord, row)
+		}
+		v = v.Elem()
+	}
+
+	for _, field := range b.fields {
+		value, err := v.FieldByIndexErr(field.index)
+		if err != nil {
+			return fmt.Errorf("%w: row %d: %w", ErrInvalidRecord, row, err)
+		}
+
+		switch field.slot.kind {
+		case FloatFeature:
+			floats[field.slot.index] = reflectFloat32(value)
+		case CatFeature:
+			cats[field.slot.index] = reflectCatString(value)
+		case TextFeature:
+			texts[field.slot.index] = value.String()
+		}
+	}
+
+	return nil
+}
+
+// reflectFloat32 converts numeric value to float32.
+func reflectFloat32(v reflect.Value) float32 {
+	switch {
+	case v.CanFloat():
+		return float32(v.Float())
+	case v.CanInt():
+		return float32(v.Int())
+	default:
+		return float32(v.Uint())
+	}
+}
+
+// reflectCatString converts string or numeric value to value of categorical feature.
+func reflectCatString(v reflect.Value) string {
+	switch {
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32)
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	default:
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+}
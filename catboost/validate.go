@@ -0,0 +1,110 @@
+package catboost
+
+import (
+	"fmt"
+)
+
+// FeatureKind is a kind of model feature.
+type FeatureKind string
+
+const (
+	FloatFeature     FeatureKind = "float"
+	CatFeature       FeatureKind = "cat"
+	TextFeature      FeatureKind = "text"
+	EmbeddingFeature FeatureKind = "embedding"
+)
+
+// ShapeError is an error of input data which does not match the model.
+// Use errors.Is(err, ErrInvalidShape) for check.
+type ShapeError struct {
+	// Kind is kind of features with invalid shape.
+	Kind FeatureKind
+	// Row is index of sample with invalid number of features, -1 if number of samples is invalid.
+	Row int
+	// Got and Expected are number of features in row (or number of samples if Row is -1).
+	// Row must have exactly Expected features.
+	Got      int
+	Expected int
+}
+
+// Error returns message of error.
+func (e *ShapeError) Error() string {
+	if e.Row < 0 {
+		return fmt.Sprintf("%s: got %d samples of %s features, expected %d", ErrInvalidShape, e.Got, e.Kind, e.Expected)
+	}
+
+	return fmt.Sprintf(
+		"%s: got %d %s features in row %d, expected %d",
+		ErrInvalidShape, e.Got, e.Kind, e.Row, e.Expected,
+	)
+}
+
+// Unwrap returns ErrInvalidShape.
+func (e *ShapeError) Unwrap() error {
+	return ErrInvalidShape
+}
+
+// SetInputValidation enables or disables validation of input data before inference (enabled by default).
+// Disable it only for trusted hot paths: invalid input without validation leads to undefined behavior.
+func (m *Model) SetInputValidation(enabled bool) {
+	m.skipValidation.Store(!enabled)
+}
+
+// checkInput returns first error of checks if input validation is enabled.
+func (m *Model) checkInput(checks ...func() error) error {
+	if m.skipValidation.Load() {
+		return nil
+	}
+
+	for _, check := range checks {
+		if err := check(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkRows returns check of rows of kind: every sample must have exactly expected number of features.
+// Rows may be empty if model has no features of kind.
+func checkRows[T any](kind FeatureKind, rows [][]T, nSamples, expected int) func() error {
+	return func() error {
+		if len(rows) == 0 && expected == 0 {
+			return nil
+		}
+
+		if len(rows) != nSamples {
+			return &ShapeError{Kind: kind, Row: -1, Got: len(rows), Expected: nSamples}
+		}
+
+		for i, row := range rows {
+			if len(row) != expected {
+				return &ShapeError{Kind: kind, Row: i, Got: len(row), Expected: expected}
+			}
+		}
+
+		return nil
+	}
+}
+
+// checkRow returns check of single sample of kind.
+func checkRow[T any](kind FeatureKind, row []T, expected int) func() error {
+	return func() error {
+		if len(row) != expected {
+			return &ShapeError{Kind: kind, Row: 0, Got: len(row), Expected: expected}
+		}
+
+		return nil
+	}
+}
+
+// checkColumns returns check of number of columns in flat input, all model features are float features there.
+func checkColumns(nCols, expected int) func() error {
+	return func() error {
+		if nCols != expected {
+			return &ShapeError{Kind: FloatFeature, Row: 0, Got: nCols, Expected: expected}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,225 @@
+// Command catboostgen generates Go input struct for CatBoost model.
+//
+// The struct has a field per model feature with `catboost` tag and implements
+// catboost.FeatureEncoder, so catboost.PredictStructs encodes it without reflection.
+// Regenerate it when the model changes: fields of removed or renamed features
+// disappear and code using them fails to compile. Use -check in CI to fail if
+// the generated file is stale.
+//
+// Usage:
+//
+//	//go:generate catboostgen -model titanic.cbm -type Passenger
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mirecl/catboost-cgo/catboost"
+)
+
+var errStale = errors.New("generated file is stale, run go generate")
+
+type config struct {
+	model    string
+	typeName string
+	pkg      string
+	output   string
+	check    bool
+}
+
+// feature is a model feature with its field of struct.
+type feature struct {
+	name  string
+	field string
+	kind  catboost.FeatureKind
+	index int
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("catboostgen: ")
+
+	cfg := config{}
+	flag.StringVar(&cfg.model, "model", "", "path to model file (required)")
+	flag.StringVar(&cfg.typeName, "type", "", "name of generated struct (required)")
+	flag.StringVar(&cfg.pkg, "package", os.Getenv("GOPACKAGE"), "package of generated file, $GOPACKAGE by default")
+	flag.StringVar(&cfg.output, "output", "", "output file, <type>_catboost.go by default")
+	flag.BoolVar(&cfg.check, "check", false, "fail if output file differs from generated one instead of writing it")
+	flag.Parse()
+
+	if err := run(cfg); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func run(cfg config) error {
+	if cfg.model == "" || cfg.typeName == "" {
+		return errors.New("-model and -type are required")
+	}
+
+	if cfg.pkg == "" {
+		cfg.pkg = "main"
+	}
+
+	if cfg.output == "" {
+		cfg.output = strings.ToLower(cfg.typeName) + "_catboost.go"
+	}
+
+	src, err := generate(cfg)
+	if err != nil {
+		return err
+	}
+
+	if !cfg.check {
+		return os.WriteFile(cfg.output, src, 0o644) //nolint:gosec
+	}
+
+	current, err := os.ReadFile(cfg.output)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(current, src) {
+		return fmt.Errorf("%w: %s", errStale, cfg.output)
+	}
+
+	return nil
+}
+
+// generate returns source of struct for features of model.
+func generate(cfg config) ([]byte, error) {
+	model, err := catboost.LoadFullModelFromFile(cfg.model)
+	if err != nil {
+		return nil, err
+	}
+	defer model.Close()
+
+	features, err := modelFeatures(model)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "// Code generated by catboostgen from %s. DO NOT EDIT.\n\n", filepath.Base(cfg.model))
+	fmt.Fprintf(&b, "package %s\n\n", cfg.pkg)
+	fmt.Fprintf(&b, "import %q\n\n", "github.com/mirecl/catboost-cgo/catboost")
+
+	fmt.Fprintf(&b, "// %s is input of model %s.\n", cfg.typeName, filepath.Base(cfg.model))
+	fmt.Fprintf(&b, "type %s struct {\n", cfg.typeName)
+	for _, f := range features {
+		goType := "string"
+		if f.kind == catboost.FloatFeature {
+			goType = "float32"
+		}
+		fmt.Fprintf(&b, "%s %s `catboost:\"%s,%s\"`\n", f.field, goType, f.name, f.kind)
+	}
+	b.WriteString("}\n\n")
+
+	fmt.Fprintf(&b, "var _ catboost.FeatureEncoder = (*%s)(nil)\n\n", cfg.typeName)
+
+	b.WriteString("// FeatureNames returns names of float, cat and text features of model.\n")
+	fmt.Fprintf(&b, "func (*%s) FeatureNames() (floats, cats, texts []string) {\n", cfg.typeName)
+	b.WriteString("return ")
+	for i, kind := range []catboost.FeatureKind{catboost.FloatFeature, catboost.CatFeature, catboost.TextFeature} {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(namesLiteral(features, kind))
+	}
+	b.WriteString("\n}\n\n")
+
+	b.WriteString("// EncodeFeatures sets features into slices of model feature counts.\n")
+	fmt.Fprintf(&b, "func (r *%s) EncodeFeatures(floats []float32, cats, texts []string) {\n", cfg.typeName)
+	for _, f := range features {
+		fmt.Fprintf(&b, "%ss[%d] = r.%s\n", f.kind, f.index, f.field)
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// modelFeatures returns features of model ordered by flat index.
+func modelFeatures(model *catboost.Model) ([]feature, error) {
+	schema, err := model.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(schema.Names(catboost.EmbeddingFeature)) > 0 {
+		return nil, errors.New("embedding features are not supported")
+	}
+
+	features := make([]feature, len(schema.Features))
+
+	positions := make(map[catboost.FeatureKind]int)
+	for i, f := range schema.Features {
+		features[i] = feature{name: f.Name, kind: f.Kind, index: positions[f.Kind]}
+		positions[f.Kind]++
+	}
+
+	seen := make(map[string]bool)
+	for i := range features {
+		field := fieldName(features[i].name)
+		for n := 2; seen[field]; n++ {
+			field = fieldName(features[i].name) + "_" + strconv.Itoa(n)
+		}
+		seen[field] = true
+
+		features[i].field = field
+	}
+
+	return features, nil
+}
+
+// fieldName returns exported Go identifier for feature name, e.g. Column=0 is Column0.
+func fieldName(name string) string {
+	var b strings.Builder
+
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+
+	field := b.String()
+	if field == "" || !unicode.IsUpper([]rune(field)[0]) {
+		field = "F" + field
+	}
+
+	return field
+}
+
+// namesLiteral returns Go literal of names of features of kind, nil if there are no such features.
+func namesLiteral(features []feature, kind catboost.FeatureKind) string {
+	var names []string
+	for _, f := range features {
+		if f.kind == kind {
+			names = append(names, strconv.Quote(f.name))
+		}
+	}
+
+	if len(names) == 0 {
+		return "nil"
+	}
+
+	return "[]string{" + strings.Join(names, ", ") + "}"
+}
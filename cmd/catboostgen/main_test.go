@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testModelPathMetadata = "../../example/metadata/metadata.cbm"
+
+func TestFieldName(t *testing.T) {
+	require.Equal(t, "Column0", fieldName("Column=0"))
+	require.Equal(t, "CatColumn1", fieldName("CatColumn_1"))
+	require.Equal(t, "SibSp", fieldName("SibSp"))
+	require.Equal(t, "PassengerId", fieldName("passenger id"))
+	require.Equal(t, "F0", fieldName("0"))
+	require.Equal(t, "F", fieldName("?"))
+}
+
+func TestGenerate(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "input_catboost.go")
+
+	cfg := config{model: testModelPathMetadata, typeName: "Input", pkg: "example", output: output}
+	require.NoError(t, run(cfg))
+
+	src, err := os.ReadFile(output)
+	require.NoError(t, err)
+	require.Contains(t, string(src), "Column0    float32 `catboost:\"Column=0,float\"`")
+	require.Contains(t, string(src), "CatColumn1 string  `catboost:\"CatColumn_1,cat\"`")
+	require.Contains(t, string(src), "cats[1] = r.CatColumn2")
+
+	cfg.check = true
+	require.NoError(t, run(cfg))
+
+	require.NoError(t, os.WriteFile(output, []byte("package example\n"), 0o600))
+	require.ErrorIs(t, run(cfg), errStale)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	cb "github.com/mirecl/catboost-cgo/catboost"
+)
+
+func main() {
+	// Load model trained with embedding features (see embedding.py)
+	model, err := cb.LoadFullModelFromFile("example/embedding/embedding.cbm")
+	if err != nil {
+		log.Fatalf("LoadFullModelFromFile: %v", err)
+	}
+	defer model.Delete()
+
+	// Inspect embedding feature metadata
+	fmt.Printf("Embedding feature count: %d\n", model.GetEmbeddingFeaturesCount())
+	fmt.Printf("Float feature count    : %d\n", model.GetFloatFeaturesCount())
+
+	embeddingIndices, err := model.GetEmbeddingFeatureIndices()
+	if err != nil {
+		log.Fatalf("GetEmbeddingFeatureIndices: %v", err)
+	}
+	fmt.Printf("Embedding feature indices: %v\n", embeddingIndices)
+
+	// Eval samples matching embedding.py:
+	//   [50.0, [1.0, 0.5, -0.25, 0.25]]
+	//   [20.0, [-1.0, -0.75, 0.125, 0.0]]
+	floats := [][]float32{{50.0}, {20.0}}
+	cats := [][]string{{}, {}}
+	texts := [][]string{{}, {}}
+	embeddings := [][][]float32{
+		{{1.0, 0.5, -0.25, 0.25}},
+		{{-1.0, -0.75, 0.125, 0.0}},
+	}
+
+	// Batch prediction
+	batchPreds, err := model.PredictTextAndEmbeddings(floats, cats, texts, embeddings)
+	if err != nil {
+		log.Fatalf("PredictTextAndEmbeddings: %v", err)
+	}
+	fmt.Println("Batch RawFormulaVal predictions:")
+	for i, p := range batchPreds {
+		fmt.Printf("  sample[%d] = %.10f\n", i, p)
+	}
+
+	// Single-sample prediction (first eval sample)
+	singlePreds, err := model.PredictSingleTextAndEmbeddings(floats[0], cats[0], texts[0], embeddings[0])
+	if err != nil {
+		log.Fatalf("PredictSingleTextAndEmbeddings: %v", err)
+	}
+	fmt.Printf("Single RawFormulaVal prediction: %.10f\n", singlePreds[0])
+}
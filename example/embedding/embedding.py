@@ -0,0 +1,73 @@
+import json
+import pathlib
+
+import numpy as np
+import pandas as pd
+from catboost import CatBoostClassifier, Pool
+
+path = pathlib.Path(__file__).parent.resolve()
+
+rng = np.random.default_rng(42)
+
+# ---------------------------------------------------------------------------
+# Train data: one float feature and one embedding feature of dimension 4.
+# Label depends on the embedding, so the model really uses it.
+# ---------------------------------------------------------------------------
+size = 200
+vectors = rng.normal(size=(size, 4))
+train_data = pd.DataFrame(
+    {
+        "price": rng.uniform(10.0, 100.0, size),
+        "vector": list(vectors),
+    }
+)
+train_labels = (vectors[:, 0] + vectors[:, 1] > 0).astype(int)
+
+eval_floats = [[50.0], [20.0]]
+eval_embeddings = [
+    [[1.0, 0.5, -0.25, 0.25]],
+    [[-1.0, -0.75, 0.125, 0.0]],
+]
+eval_data = pd.DataFrame(
+    {
+        "price": [row[0] for row in eval_floats],
+        "vector": [np.array(row[0]) for row in eval_embeddings],
+    }
+)
+
+train_pool = Pool(data=train_data, label=train_labels, embedding_features=["vector"])
+eval_pool = Pool(data=eval_data, embedding_features=["vector"])
+
+# ---------------------------------------------------------------------------
+# Train
+# ---------------------------------------------------------------------------
+model = CatBoostClassifier(
+    iterations=50,
+    learning_rate=0.1,
+    depth=4,
+    loss_function="Logloss",
+    random_seed=42,
+)
+
+model.fit(train_pool, silent=True)
+
+# ---------------------------------------------------------------------------
+# Predict on eval samples, predictions are checked by Go tests
+# ---------------------------------------------------------------------------
+preds_raw = model.predict(eval_pool, prediction_type="RawFormulaVal")
+print(f"Preds `RawFormulaVal` : {preds_raw.tolist()}")
+
+model.save_model(f"{path}/embedding.cbm")
+print(f"Model saved to {path}/embedding.cbm")
+
+with open(f"{path}/embedding.json", "w") as f:
+    json.dump(
+        {
+            "floats": eval_floats,
+            "embeddings": eval_embeddings,
+            "preds": preds_raw.tolist(),
+        },
+        f,
+        indent=2,
+    )
+print(f"Predictions saved to {path}/embedding.json")